@@ -100,7 +100,7 @@ func TestAnalysis(t *testing.T) {
 			}
 
 			log := testutil.TestLogger(t)
-			graph, err := depgraph.GetGraph(log, testDir)
+			graph, err := depgraph.GetGraph(log, testDir, nil)
 			require.NoError(t, err)
 
 			analysis, err := Analyse(log.Log(), graph)
@@ -0,0 +1,244 @@
+package depgraph
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/Helcaraxan/gomod/internal/graph"
+	"github.com/Helcaraxan/gomod/internal/logger"
+	"github.com/Helcaraxan/gomod/internal/query"
+)
+
+// Rule is a constraint on the dependency graph that is expressed via queries. The nodes selected by
+// its query violate the rule unless they are also selected by the query of allowed nodes.
+type Rule struct {
+	Name string
+	// Nodes that violate the rule unless they are allowed.
+	Query query.Expr
+	// Nodes that may be selected by Query. When nil no node may be selected.
+	Allowed query.Expr
+	// Nodes from which the dependency chains leading to violations are shown. When nil the chains
+	// start at the main module.
+	From query.Expr
+	// Level of the graph at which the queries are evaluated.
+	Level Level
+}
+
+// Violation is an import or dependency of a node selected by the query of a rule, without being
+// allowed, together with the shortest chain of dependencies leading from the rule's starting nodes
+// through the importing node to the violating one. Nodes that can not be reached from the starting
+// nodes are reported without a source and with an empty chain.
+type Violation struct {
+	Source string
+	Target string
+	Chain  []string
+}
+
+func (v Violation) String() string {
+	if v.Source == "" {
+		return v.Target
+	}
+	return v.Source + " -> " + v.Target
+}
+
+// CheckResult holds the violations of a rule. The rule passes if there are none.
+type CheckResult struct {
+	Rule       *Rule
+	Violations []Violation
+}
+
+// CheckRule returns the violations of the given rule, ordered by their target and source nodes.
+func (g *DepGraph) CheckRule(dl *logger.Builder, rule *Rule) (*CheckResult, error) {
+	log := dl.Domain(logger.QueryDomain)
+	log.Debug("Checking rule.", zap.String("rule", rule.Name), zap.Stringer("query", rule.Query))
+
+	// A passing rule selects no nodes so empty query results are not worth a warning.
+	quiet := log.DemoteWarnings()
+
+	result := &CheckResult{Rule: rule}
+	selected, err := g.computeSet(quiet, rule.Query, rule.Level)
+	if err != nil {
+		return nil, err
+	}
+	if rule.Allowed != nil {
+		allowed, err := g.computeSet(quiet, rule.Allowed, rule.Level)
+		if err != nil {
+			return nil, err
+		}
+		selected = selected.subtract(allowed)
+	}
+	if len(selected) == 0 {
+		return result, nil
+	}
+
+	var sources []graph.Node
+	if rule.From != nil {
+		from, err := g.computeSet(quiet, rule.From, rule.Level)
+		if err != nil {
+			return nil, err
+		}
+		for name := range from {
+			node, _ := g.Graph.GetNode(nodeHash(name, rule.Level))
+			sources = append(sources, node)
+		}
+	} else if rule.Level == LevelPackages {
+		sources = g.Main.packages.List()
+	} else {
+		sources = []graph.Node{g.Main}
+	}
+	sort.Slice(sources, func(i int, j int) bool { return sources[i].Name() < sources[j].Name() })
+
+	chains := NewDependencyChains(sources)
+	for name := range selected {
+		node, _ := g.Graph.GetNode(nodeHash(name, rule.Level))
+		violations := chains.incomingViolations(node)
+		if len(violations) == 0 {
+			violations = []Violation{{Target: name}}
+		}
+		result.Violations = append(result.Violations, violations...)
+	}
+	sort.Slice(result.Violations, func(i int, j int) bool {
+		vi, vj := result.Violations[i], result.Violations[j]
+		if vi.Target != vj.Target {
+			return vi.Target < vj.Target
+		}
+		return vi.Source < vj.Source
+	})
+	return result, nil
+}
+
+// Print reports whether the rule passed and, if not, lists the violating imports together with the
+// chain of dependencies through which each of them is reached.
+func (r *CheckResult) Print(w io.Writer) error {
+	if len(r.Violations) == 0 {
+		_, err := fmt.Fprintf(w, "PASS %s\n", r.Rule.Name)
+		return err
+	}
+
+	plural := "s"
+	if len(r.Violations) == 1 {
+		plural = ""
+	}
+	lines := []string{fmt.Sprintf("FAIL %s: %d violation%s", r.Rule.Name, len(r.Violations), plural)}
+	for _, violation := range r.Violations {
+		lines = append(lines, "  "+violation.String())
+		if len(violation.Chain) > 2 {
+			lines = append(lines, "    "+strings.Join(violation.Chain, " -> "))
+		}
+	}
+	_, err := fmt.Fprintln(w, strings.Join(lines, "\n"))
+	return err
+}
+
+// DependencyChains finds the shortest chains of dependencies through which nodes are reached from a
+// set of source nodes. Chains that only consist of non-test dependencies are preferred as they show
+// why a node ends up in production code.
+type DependencyChains struct {
+	nonTest *dependencyChains
+	all     *dependencyChains
+}
+
+func NewDependencyChains(sources []graph.Node) *DependencyChains {
+	return &DependencyChains{
+		nonTest: newDependencyChains(sources, true),
+		all:     newDependencyChains(sources, false),
+	}
+}
+
+// Chain returns the names of the nodes on the chain leading from one of the sources to the given
+// node. The chain is empty if the node can not be reached.
+func (c *DependencyChains) Chain(name string) []string {
+	if chain, ok := c.nonTest.chain(name); ok {
+		return chain
+	}
+	chain, _ := c.all.chain(name)
+	return chain
+}
+
+// incomingViolations returns the edges through which the given node is reached from the sources.
+// As for chains, edges that are part of a chain of non-test dependencies are preferred.
+func (c *DependencyChains) incomingViolations(node graph.Node) []Violation {
+	for _, chains := range []*dependencyChains{c.nonTest, c.all} {
+		var violations []Violation
+		for _, pred := range node.Predecessors().List() {
+			chain, ok := chains.chain(pred.Name())
+			if !ok || (chains == c.nonTest && !isNonTestDependency(pred, node)) {
+				continue
+			}
+			violations = append(violations, Violation{Source: pred.Name(), Target: node.Name(), Chain: append(chain, node.Name())})
+		}
+		if len(violations) > 0 {
+			return violations
+		}
+	}
+	return nil
+}
+
+// dependencyChains holds the result of a breadth-first traversal that records through which node
+// each of the reached nodes was first encountered.
+type dependencyChains struct {
+	parents map[string]string
+	reached map[string]bool
+}
+
+func newDependencyChains(sources []graph.Node, nonTestOnly bool) *dependencyChains {
+	c := &dependencyChains{parents: map[string]string{}, reached: map[string]bool{}}
+	var todo []graph.Node
+	for _, source := range sources {
+		if !c.reached[source.Name()] {
+			c.reached[source.Name()] = true
+			todo = append(todo, source)
+		}
+	}
+	for len(todo) > 0 {
+		next := todo[0]
+		todo = todo[1:]
+		for _, dep := range next.Successors().List() {
+			if c.reached[dep.Name()] || (nonTestOnly && !isNonTestDependency(next, dep)) {
+				continue
+			}
+			c.reached[dep.Name()] = true
+			c.parents[dep.Name()] = next.Name()
+			todo = append(todo, dep)
+		}
+	}
+	return c
+}
+
+// isNonTestDependency indicates whether the dependency of the source on the target is not only
+// required by tests. For packages this is the case when the target is imported by the source's
+// non-test files.
+func isNonTestDependency(source graph.Node, target graph.Node) bool {
+	p, ok := source.(*Package)
+	if !ok {
+		return !target.(testAnnotated).isTestDependency()
+	}
+	if strings.HasSuffix(p.Info.Name, "_test") {
+		return false
+	}
+	for _, imp := range p.Info.Imports {
+		if imp == target.Name() {
+			return true
+		}
+	}
+	return false
+}
+
+// chain returns the shortest chain of node names leading from one of the sources to the given node.
+func (c *dependencyChains) chain(name string) ([]string, bool) {
+	if !c.reached[name] {
+		return nil, false
+	}
+	chain := []string{name}
+	for parent, ok := c.parents[name]; ok; parent, ok = c.parents[parent] {
+		chain = append(chain, parent)
+	}
+	for i, j := 0, len(chain)-1; i < j; i, j = i+1, j-1 {
+		chain[i], chain[j] = chain[j], chain[i]
+	}
+	return chain, true
+}
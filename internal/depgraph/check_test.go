@@ -0,0 +1,148 @@
+package depgraph
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Helcaraxan/gomod/internal/query"
+	"github.com/Helcaraxan/gomod/internal/testutil"
+)
+
+func TestCheckRule(t *testing.T) {
+	cwd, err := os.Getwd()
+	require.NoError(t, err)
+
+	testcases := map[string]struct {
+		level      Level
+		query      string
+		allowed    string
+		from       string
+		expected   []Violation
+		expectedOK bool
+	}{
+		"Layering": {
+			level: LevelPackages,
+			query: "deps(ourorg.com/svc/api) inter ourorg.com/svc/storage",
+			from:  "ourorg.com/svc/api",
+			expected: []Violation{
+				{Source: "ourorg.com/svc/internal/repo", Target: "ourorg.com/svc/storage", Chain: []string{"ourorg.com/svc/api", "ourorg.com/svc/internal/repo", "ourorg.com/svc/storage"}},
+			},
+		},
+		"Passing": {
+			level:      LevelPackages,
+			query:      "deps(ourorg.com/svc/storage) inter ourorg.com/svc/api",
+			expectedOK: true,
+		},
+		"NonTestChain": {
+			level: LevelPackages,
+			query: "github.com/stretchr/testify/**",
+			expected: []Violation{
+				{Source: "ourorg.com/svc/internal/testhelpers", Target: "github.com/stretchr/testify/assert", Chain: []string{"ourorg.com/svc/internal/testhelpers", "github.com/stretchr/testify/assert"}},
+			},
+		},
+		"TestChain": {
+			level: LevelPackages,
+			query: "github.com/stretchr/testify/require:test",
+			expected: []Violation{
+				{Source: "ourorg.com/svc/api", Target: "github.com/stretchr/testify/require", Chain: []string{"ourorg.com/svc/api", "github.com/stretchr/testify/require"}},
+			},
+		},
+		"Subset": {
+			level:   LevelModules,
+			query:   "deps(ourorg.com/svc)",
+			allowed: "ourorg.com/svc + example.com/**",
+			expected: []Violation{
+				{Source: "ourorg.com/svc", Target: "github.com/stretchr/testify", Chain: []string{"ourorg.com/svc", "github.com/stretchr/testify"}},
+			},
+		},
+		"Unreachable": {
+			level: LevelPackages,
+			query: "example.com/db",
+			from:  "ourorg.com/svc/internal/testhelpers",
+			expected: []Violation{
+				{Target: "example.com/db"},
+			},
+		},
+	}
+
+	for name := range testcases {
+		testcase := testcases[name]
+		t.Run(name, func(t *testing.T) {
+			testDir := testutil.SetupTestModule(t, filepath.Join(cwd, "testdata", "Check.yaml"), &testutil.GraphTestDefinition{})
+
+			log := testutil.TestLogger(t)
+			g, err := GetGraph(log, testDir, nil)
+			require.NoError(t, err)
+
+			rule := &Rule{Name: name, Level: testcase.level}
+			for _, q := range []struct {
+				raw    string
+				target *query.Expr
+			}{
+				{raw: testcase.query, target: &rule.Query},
+				{raw: testcase.allowed, target: &rule.Allowed},
+				{raw: testcase.from, target: &rule.From},
+			} {
+				if q.raw != "" {
+					*q.target, err = query.Parse(log, q.raw)
+					require.NoError(t, err)
+				}
+			}
+
+			result, err := g.CheckRule(log, rule)
+			require.NoError(t, err)
+			if testcase.expectedOK {
+				assert.Empty(t, result.Violations)
+			} else {
+				assert.Equal(t, testcase.expected, result.Violations)
+			}
+		})
+	}
+}
+
+func TestCheckResultPrint(t *testing.T) {
+	testcases := map[string]struct {
+		violations []Violation
+		expected   string
+	}{
+		"Pass": {
+			expected: "PASS layering\n",
+		},
+		"SingleViolation": {
+			violations: []Violation{
+				{Source: "ourorg.com/svc/api", Target: "ourorg.com/svc/storage", Chain: []string{"ourorg.com/svc/api", "ourorg.com/svc/storage"}},
+			},
+			expected: `FAIL layering: 1 violation
+  ourorg.com/svc/api -> ourorg.com/svc/storage
+`,
+		},
+		"MultipleViolations": {
+			violations: []Violation{
+				{Source: "ourorg.com/svc/internal/repo", Target: "ourorg.com/svc/storage", Chain: []string{"ourorg.com/svc/api", "ourorg.com/svc/internal/repo", "ourorg.com/svc/storage"}},
+				{Target: "example.com/db"},
+			},
+			expected: `FAIL layering: 2 violations
+  ourorg.com/svc/internal/repo -> ourorg.com/svc/storage
+    ourorg.com/svc/api -> ourorg.com/svc/internal/repo -> ourorg.com/svc/storage
+  example.com/db
+`,
+		},
+	}
+
+	for name := range testcases {
+		testcase := testcases[name]
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var out bytes.Buffer
+			result := &CheckResult{Rule: &Rule{Name: "layering"}, Violations: testcase.violations}
+			require.NoError(t, result.Print(&out))
+			assert.Equal(t, testcase.expected, out.String())
+		})
+	}
+}
@@ -0,0 +1,271 @@
+package depgraph
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/bmatcuk/doublestar/v3"
+	"go.uber.org/zap"
+
+	"github.com/Helcaraxan/gomod/internal/graph"
+	"github.com/Helcaraxan/gomod/internal/logger"
+)
+
+var ErrInvalidCollapsePattern = errors.New("invalid collapse pattern")
+
+// Collapsed is a synthetic node that stands in for all the modules or packages of a graph whose
+// names match the same pattern. Its edges are the union of the edges of its members, with the
+// weight of each edge corresponding to the number of edges that were merged into it.
+type Collapsed struct {
+	pattern string
+	level   Level
+	members []string
+	parent  *Collapsed
+
+	predecessors graph.NodeRefs
+	successors   graph.NodeRefs
+	children     graph.NodeRefs
+
+	isNonTestDependency bool
+}
+
+func newCollapsed(pattern string, level Level, parent *Collapsed) *Collapsed {
+	return &Collapsed{
+		pattern:      pattern,
+		level:        level,
+		parent:       parent,
+		predecessors: graph.NewNodeRefs(),
+		successors:   graph.NewNodeRefs(),
+		children:     graph.NewNodeRefs(),
+	}
+}
+
+// Name of the collapsed node which is the pattern matching its members.
+func (c *Collapsed) Name() string {
+	return c.pattern
+}
+
+func (c *Collapsed) Hash() string {
+	return collapsedHash(c.pattern, c.level)
+}
+
+func (c *Collapsed) String() string {
+	return fmt.Sprintf("%s, members: %d, preds: [%s], succs: [%s]", c.Hash(), len(c.members), c.predecessors, c.successors)
+}
+
+func collapsedHash(pattern string, level Level) string {
+	if level == LevelPackages {
+		return "collapsed packages " + pattern
+	}
+	return "collapsed modules " + pattern
+}
+
+// Members returns the names of the nodes that were collapsed into this one.
+func (c *Collapsed) Members() []string {
+	return append([]string{}, c.members...)
+}
+
+func (c *Collapsed) Parent() graph.Node {
+	if c.parent == nil {
+		return nil
+	}
+	return c.parent
+}
+
+func (c *Collapsed) Predecessors() *graph.NodeRefs {
+	return &c.predecessors
+}
+
+func (c *Collapsed) Successors() *graph.NodeRefs {
+	return &c.successors
+}
+
+func (c *Collapsed) Children() *graph.NodeRefs {
+	return &c.children
+}
+
+func (c *Collapsed) Copy(parent graph.Node) graph.Node {
+	var p *Collapsed
+	if parent != nil {
+		p = parent.(*Collapsed)
+	}
+	n := newCollapsed(c.pattern, c.level, p)
+	n.members = c.Members()
+	n.isNonTestDependency = c.isNonTestDependency
+	return n
+}
+
+func (c *Collapsed) NodeAttributes(annotate bool, highlight Highlight) []string {
+	var annotations []string
+
+	text, background := hashToColourHSV(collapsedHash(c.pattern, LevelModules), c.isTestDependency(), highlight)
+	annotations = append(annotations, fmt.Sprintf(`fontcolor="%s"`, text), fmt.Sprintf(`fillcolor="%s"`, background))
+	annotations = append(annotations, nodeHighlightAttributes(highlight)...)
+
+	kind := "modules"
+	if c.level == LevelPackages {
+		kind = "packages"
+	}
+	return append(
+		annotations,
+		"peripheries=2",
+		fmt.Sprintf("label=<%s<br /><font point-size=\"10\">%d %s</font>>", c.pattern, len(c.members), kind),
+	)
+}
+
+func (c *Collapsed) EdgeAttributes(target graph.Node, annotate bool, weights bool, highlight Highlight) []string {
+	var annotations []string
+	if highlight != HighlightNone {
+		annotations = append(annotations, edgeHighlightAttributes(highlight)...)
+	} else if target.(testAnnotated).isTestDependency() {
+		annotations = append(annotations, "color=lightblue") //nolint:misspell
+	}
+	return append(annotations, mergedEdgeAttributes(c, target)...)
+}
+
+// NodeData returns the properties of the collapsed node that are exported to formats meant to be
+// processed by graph analysis tools.
+func (c *Collapsed) NodeData() map[string]interface{} {
+	return map[string]interface{}{
+		"path":         c.pattern,
+		"test_only":    c.isTestDependency(),
+		"member_count": len(c.members),
+	}
+}
+
+// EdgeData returns the properties of the edge towards the target that are exported to formats
+// meant to be processed by graph analysis tools.
+func (c *Collapsed) EdgeData(target graph.Node) map[string]interface{} {
+	data := map[string]interface{}{
+		"test_only": target.(testAnnotated).isTestDependency(),
+	}
+	if count, ok := mergedEdgeCount(c, target); ok {
+		data["edge_count"] = count
+	}
+	return data
+}
+
+func (c *Collapsed) isTestDependency() bool {
+	return !c.isNonTestDependency
+}
+
+// mergedEdgeCount returns the number of edges of the original graph that are represented by the
+// edge between the source and target if either of them is a collapsed node.
+func mergedEdgeCount(source graph.Node, target graph.Node) (int, bool) {
+	_, sourceCollapsed := source.(*Collapsed)
+	_, targetCollapsed := target.(*Collapsed)
+	if !sourceCollapsed && !targetCollapsed {
+		return 0, false
+	}
+	_, count := source.Successors().Get(target.Hash())
+	return count, true
+}
+
+// mergedEdgeAttributes labels an edge with the number of edges that were merged into it, if that
+// is more than one.
+func mergedEdgeAttributes(source graph.Node, target graph.Node) []string {
+	if count, ok := mergedEdgeCount(source, target); ok && count > 1 {
+		return []string{fmt.Sprintf("label=<<font point-size=\"10\">%d edges</font>>", count)}
+	}
+	return nil
+}
+
+// Collapse returns a new graph in which all nodes at the given level whose names match one of the
+// patterns are merged into a single synthetic node per pattern. A node matching several patterns
+// is merged into the node of the first one. Edges between members of the same collapsed node are
+// dropped while all others are redirected to the collapsed nodes. The receiver is not modified so
+// that collapsing does not interfere with any further queries.
+func (g *DepGraph) Collapse(dl *logger.Builder, patterns []string, level Level) (*DepGraph, error) {
+	log := dl.Domain(logger.GraphDomain)
+
+	c, err := g.Clone()
+	if err != nil {
+		return nil, err
+	}
+
+	groups := map[string]*Collapsed{}
+	var groupList []*Collapsed
+	members := map[string]*Collapsed{}
+	nodes := c.Graph.GetLevel(int(level)).List()
+	for _, node := range nodes {
+		for _, pattern := range patterns {
+			matches, err := doublestar.Match(pattern, node.Name())
+			if err != nil {
+				log.Error("Invalid collapse pattern.", zap.String("pattern", pattern), zap.Error(err))
+				return nil, fmt.Errorf("%q: %w", pattern, ErrInvalidCollapsePattern)
+			} else if !matches {
+				continue
+			}
+			group, ok := groups[pattern]
+			if !ok {
+				var parent *Collapsed
+				if level == LevelPackages {
+					parent = newCollapsed(pattern, LevelModules, nil)
+				}
+				group = newCollapsed(pattern, level, parent)
+				groups[pattern] = group
+				groupList = append(groupList, group)
+			}
+			log.Debug("Collapsing node.", zap.String("node", node.Name()), zap.String("pattern", pattern))
+			group.members = append(group.members, node.Name())
+			if !node.(testAnnotated).isTestDependency() {
+				group.isNonTestDependency = true
+				if group.parent != nil {
+					group.parent.isNonTestDependency = true
+				}
+			}
+			members[node.Hash()] = group
+			break
+		}
+	}
+
+	for _, group := range groupList {
+		if group.parent != nil {
+			if err = c.Graph.AddNode(group.parent); err != nil {
+				return nil, err
+			}
+			group.parent.members = group.members
+		}
+		if err = c.Graph.AddNode(group); err != nil {
+			return nil, err
+		}
+	}
+
+	collapsed := func(n graph.Node) graph.Node {
+		if group, ok := members[n.Hash()]; ok {
+			return group
+		}
+		return n
+	}
+	for _, node := range nodes {
+		for _, dep := range node.Successors().List() {
+			source, target := collapsed(node), collapsed(dep)
+			if source.Hash() == target.Hash() || (source == node && target == dep) {
+				continue
+			}
+			if err = c.Graph.AddEdge(source, target); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	var hashes []string
+	for hash := range members {
+		hashes = append(hashes, hash)
+	}
+	sort.Strings(hashes)
+	for _, hash := range hashes {
+		if err = c.Graph.DeleteNode(hash); err != nil {
+			return nil, err
+		}
+	}
+
+	// The main module is removed if all its packages were collapsed.
+	if c.Main != nil {
+		if _, err = c.Graph.GetNode(c.Main.Hash()); err != nil {
+			c.Main = nil
+		}
+	}
+	return c, nil
+}
@@ -0,0 +1,81 @@
+package depgraph
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Helcaraxan/gomod/internal/testutil"
+)
+
+func TestCollapse(t *testing.T) {
+	t.Parallel()
+
+	log := testutil.TestLogger(t)
+	g := instantiateQueryTestGraph(t, queryTestGraph{
+		nodes: []queryTestNode{
+			{name: "test.com/module"},
+			{name: "test.com/foo"},
+			{name: "other.com/a"},
+			{name: "other.com/b"},
+			{name: "other.com/c", isTest: true},
+			{name: "tools.com/x", isTest: true},
+		},
+		edges: []queryTestEdge{
+			{s: "test.com/module", e: "test.com/foo"},
+			{s: "test.com/module", e: "other.com/a"},
+			{s: "test.com/module", e: "other.com/c"},
+			{s: "test.com/foo", e: "other.com/b"},
+			{s: "other.com/a", e: "other.com/b"},
+			{s: "other.com/b", e: "tools.com/x"},
+			{s: "other.com/c", e: "tools.com/x"},
+		},
+	})
+
+	c, err := g.Collapse(log, []string{"other.com/**", "tools.com/**"}, LevelModules)
+	require.NoError(t, err)
+
+	var names []string
+	for _, n := range c.Graph.GetLevel(int(LevelModules)).List() {
+		names = append(names, n.Name())
+	}
+	assert.ElementsMatch(t, []string{"test.com/module", "test.com/foo", "other.com/**", "tools.com/**"}, names)
+
+	other, err := c.Graph.GetNode(collapsedHash("other.com/**", LevelModules))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"other.com/a", "other.com/b", "other.com/c"}, other.(*Collapsed).Members())
+	assert.False(t, other.(*Collapsed).isTestDependency())
+
+	tools, err := c.Graph.GetNode(collapsedHash("tools.com/**", LevelModules))
+	require.NoError(t, err)
+	assert.True(t, tools.(*Collapsed).isTestDependency())
+
+	// Edges between members are dropped while the others are merged.
+	_, weight := other.Predecessors().Get(moduleHash("test.com/module"))
+	assert.Equal(t, 2, weight)
+	_, weight = other.Predecessors().Get(moduleHash("test.com/foo"))
+	assert.Equal(t, 1, weight)
+	_, weight = other.Successors().Get(tools.Hash())
+	assert.Equal(t, 2, weight)
+	assert.Equal(t, 1, other.Successors().Len())
+
+	module, err := c.Graph.GetNode(moduleHash("test.com/module"))
+	require.NoError(t, err)
+	count, ok := mergedEdgeCount(module, other)
+	assert.True(t, ok)
+	assert.Equal(t, 2, count)
+	foo, err := c.Graph.GetNode(moduleHash("test.com/foo"))
+	require.NoError(t, err)
+	_, ok = mergedEdgeCount(module, foo)
+	assert.False(t, ok)
+
+	// The original graph is left untouched.
+	assert.Equal(t, 6, g.Graph.GetLevel(int(LevelModules)).Len())
+	_, err = g.Graph.GetNode(moduleHash("other.com/a"))
+	assert.NoError(t, err)
+
+	_, err = g.Collapse(log, []string{"other.com/[a"}, LevelModules)
+	assert.True(t, errors.Is(err, ErrInvalidCollapsePattern))
+}
@@ -6,7 +6,61 @@ import (
 	"math/bits"
 )
 
-func hashToColourHSV(hash string, isTest bool) (text string, background string) {
+// Highlight indicates how a node or edge should be emphasised relative to the rest of the graph
+// when printed.
+type Highlight uint8
+
+const (
+	// No highlighting is in effect.
+	HighlightNone Highlight = iota
+	// The node or edge is part of the highlighted selection.
+	Highlighted
+	// Another part of the graph is highlighted and this node or edge is de-emphasised.
+	Faded
+)
+
+// nodeHighlightAttributes returns the DOT attributes that set a node apart when it is highlighted.
+// The colours of faded nodes are instead toned down by hashToColourHSV.
+func nodeHighlightAttributes(highlight Highlight) []string {
+	switch highlight {
+	case Highlighted:
+		return []string{"penwidth=3", `color="0.000 1.000 0.800"`}
+	case Faded:
+		return []string{`color="0.000 0.000 0.750"`}
+	default:
+		return nil
+	}
+}
+
+// edgeHighlightAttributes returns the DOT attributes for an edge when highlighting is in effect. As
+// these set the edge's colour they take precedence over the colour marking test-only edges.
+func edgeHighlightAttributes(highlight Highlight) []string {
+	switch highlight {
+	case Highlighted:
+		return []string{"penwidth=2", "color=red"}
+	case Faded:
+		return []string{"color=lightgrey"}
+	default:
+		return nil
+	}
+}
+
+// edgeWeightAttributes returns the DOT attributes that make edges representing many imports stand
+// out. The pen width grows logarithmically with the weight and is capped to keep dense graphs legible.
+func edgeWeightAttributes(weight int) []string {
+	if weight < 2 {
+		return nil
+	}
+	width := bits.Len(uint(weight))
+	if width > maxEdgeWidth {
+		width = maxEdgeWidth
+	}
+	return []string{fmt.Sprintf("penwidth=%d", width)}
+}
+
+const maxEdgeWidth = 5
+
+func hashToColourHSV(hash string, isTest bool, highlight Highlight) (text string, background string) {
 	var h byte
 	for _, b := range []byte(hash) {
 		h ^= bits.RotateLeft8(uint8(b), int(b))
@@ -21,5 +75,9 @@ func hashToColourHSV(hash string, isTest bool) (text string, background string)
 	} else if hue < 0.10 || (hue > 0.6 && hue < 0.8) {
 		text = "0.000 0.000 1.000"
 	}
+	if highlight == Faded {
+		text = "0.000 0.000 0.600"
+		sat *= 0.3
+	}
 	return text, fmt.Sprintf("%.3f %.3f 1.000", hue, sat)
 }
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"path"
 	"strings"
 
 	"go.uber.org/zap"
@@ -35,11 +36,14 @@ func (g *DepGraph) buildImportGraph(dl *logger.Builder) error {
 		}
 
 		for _, imp := range imports {
-			if isStandardLib(imp) {
+			if g.skipImport(imp) {
 				continue
 			}
 
 			targetNode, _ := pkgs.Get(packageHash(imp))
+			if targetNode == nil && isStandardLib(imp) {
+				targetNode = g.addStdLibPackage(log, imp)
+			}
 			if targetNode == nil {
 				log.Error("Detected import of unknown package.", zap.String("package", imp))
 				continue
@@ -91,7 +95,7 @@ func (g *DepGraph) markNonTestDependencies(log *logger.Logger) error {
 		next.Parent().(*Module).isNonTestDependency = true
 
 		for _, imp := range next.(*Package).Info.Imports {
-			if isStandardLib(imp) {
+			if g.skipImport(imp) {
 				continue
 			}
 
@@ -186,6 +190,33 @@ func (g *DepGraph) retrievePackageInfo(log *logger.Logger, pkgs []string) (impor
 	return imports, nil
 }
 
+// skipImport determines whether an imported package should be left out of the dependency graph.
+// Standard library packages are only retained when explicitly requested, the 'C' pseudo-package
+// used by cgo never is.
+func (g *DepGraph) skipImport(imp string) bool {
+	return isStandardLib(imp) && (!g.stdLib || imp == "C")
+}
+
+// addStdLibPackage returns the node for the specified standard library package, creating it and
+// the synthetic module it belongs to if they are not yet part of the graph. As we do not retrieve
+// any information about standard library packages via 'go list' these nodes never have imports of
+// their own.
+func (g *DepGraph) addStdLibPackage(log *logger.Logger, imp string) graph.Node {
+	if n, err := g.Graph.GetNode(packageHash(imp)); err == nil {
+		return n
+	}
+
+	std := g.AddModule(&modules.ModuleInfo{Path: StdLibModule})
+	pkg := NewPackage(&modules.PackageInfo{
+		ImportPath: imp,
+		Name:       path.Base(imp),
+		Standard:   true,
+	}, std)
+	_ = g.Graph.AddNode(pkg)
+	log.Debug("Added standard library package.", zap.String("package", pkg.Name()))
+	return pkg
+}
+
 func isStandardLib(pkg string) bool {
 	return !strings.Contains(strings.Split(pkg, "/")[0], ".")
 }
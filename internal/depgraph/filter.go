@@ -0,0 +1,97 @@
+package depgraph
+
+import (
+	"errors"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/Helcaraxan/gomod/internal/logger"
+)
+
+var ErrUnsupportedEdgeFilter = errors.New("unsupported edge filter")
+
+// Comparison is the operator used to compare a value, such as the weight of an edge, with a
+// threshold.
+type Comparison uint8
+
+const (
+	Equal Comparison = iota
+	Greater
+	GreaterOrEqual
+	Less
+	LessOrEqual
+)
+
+func (c Comparison) String() string {
+	switch c {
+	case Equal:
+		return "="
+	case Greater:
+		return ">"
+	case GreaterOrEqual:
+		return ">="
+	case Less:
+		return "<"
+	case LessOrEqual:
+		return "<="
+	default:
+		return "unknown"
+	}
+}
+
+// Compare returns whether the comparison holds between the value a and the threshold b.
+func (c Comparison) Compare(a int, b int) bool {
+	switch c {
+	case Greater:
+		return a > b
+	case GreaterOrEqual:
+		return a >= b
+	case Less:
+		return a < b
+	case LessOrEqual:
+		return a <= b
+	default:
+		return a == b
+	}
+}
+
+// EdgeFilter selects the edges of a module-level graph based on their weight.
+type EdgeFilter struct {
+	Comparison Comparison
+	Weight     int
+}
+
+func (f EdgeFilter) String() string {
+	return fmt.Sprintf("weight%s%d", f.Comparison, f.Weight)
+}
+
+// Matches returns whether an edge with the given weight is retained by the filter.
+func (f EdgeFilter) Matches(weight int) bool {
+	return f.Comparison.Compare(weight, f.Weight)
+}
+
+// FilterEdges removes all edges between modules whose weight is not matched by the filter, together
+// with the package imports they represent. Nodes are retained even if they lose all their edges.
+// Edge weights are only defined between modules so filtering at the package level is not supported.
+func (g *DepGraph) FilterEdges(dl *logger.Builder, filter EdgeFilter, level Level) error {
+	log := dl.Domain(logger.GraphDomain)
+
+	if level != LevelModules {
+		log.Error("Edge weights are only available between modules.", zap.Stringer("filter", filter))
+		return fmt.Errorf("%q: %w", filter, ErrUnsupportedEdgeFilter)
+	}
+
+	for _, node := range g.Graph.GetLevel(int(level)).List() {
+		module := node.(*Module)
+		for _, dep := range module.Successors().List() {
+			if weight := module.EdgeWeight(dep); !filter.Matches(weight) {
+				log.Debug("Removing filtered edge.", zap.String("source", module.Name()), zap.String("target", dep.Name()), zap.Int("weight", weight))
+				if err := g.Graph.DeleteEdge(module, dep); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
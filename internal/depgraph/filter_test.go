@@ -0,0 +1,83 @@
+package depgraph
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Helcaraxan/gomod/internal/graph"
+	"github.com/Helcaraxan/gomod/internal/modules"
+	"github.com/Helcaraxan/gomod/internal/testutil"
+)
+
+func TestFilterEdges(t *testing.T) {
+	t.Parallel()
+
+	log := testutil.TestLogger(t)
+	g := DepGraph{Graph: graph.NewHierarchicalDigraph(log.Log())}
+
+	mods := map[string]*Module{}
+	for _, name := range []string{"test.com/main", "test.com/heavy", "test.com/light", "test.com/unused"} {
+		mods[name] = NewModule(&modules.ModuleInfo{Path: name})
+		require.NoError(t, g.Graph.AddNode(mods[name]))
+	}
+	main := mods["test.com/main"]
+	for _, dep := range []string{"test.com/heavy", "test.com/light", "test.com/unused"} {
+		require.NoError(t, g.Graph.AddEdge(main, mods[dep]))
+		main.VersionConstraints[moduleHash(dep)] = VersionConstraint{Target: "v1.0.0"}
+	}
+
+	pkgs := map[string]*Package{}
+	for _, name := range []string{"test.com/main/a", "test.com/main/b", "test.com/heavy/x", "test.com/heavy/y", "test.com/light/z"} {
+		var parent *Module
+		for path, module := range mods {
+			if strings.HasPrefix(name, path+"/") {
+				parent = module
+			}
+		}
+		pkgs[name] = NewPackage(&modules.PackageInfo{ImportPath: name}, parent)
+		require.NoError(t, g.Graph.AddNode(pkgs[name]))
+	}
+	for _, edge := range [][2]string{
+		{"test.com/main/a", "test.com/heavy/x"},
+		{"test.com/main/a", "test.com/heavy/y"},
+		{"test.com/main/b", "test.com/heavy/x"},
+		{"test.com/main/b", "test.com/light/z"},
+	} {
+		require.NoError(t, g.Graph.AddEdge(pkgs[edge[0]], pkgs[edge[1]]))
+	}
+
+	assert.Equal(t, 3, main.EdgeWeight(mods["test.com/heavy"]))
+	assert.Equal(t, 1, main.EdgeWeight(mods["test.com/light"]))
+	assert.Equal(t, 0, main.EdgeWeight(mods["test.com/unused"]))
+	assert.Equal(t, []string{"penwidth=2"}, edgeWeightAttributes(3))
+	assert.Empty(t, edgeWeightAttributes(1))
+
+	require.NoError(t, g.FilterEdges(log, EdgeFilter{Comparison: Greater, Weight: 1}, LevelModules))
+	assert.Equal(t, []graph.Node{mods["test.com/heavy"]}, main.Successors().List())
+	assert.Equal(t, []graph.Node{pkgs["test.com/heavy/x"]}, pkgs["test.com/main/b"].Successors().List())
+	_, err := g.Graph.GetNode(moduleHash("test.com/unused"))
+	assert.NoError(t, err, "Nodes should be retained when they lose their edges.")
+
+	err = g.FilterEdges(log, EdgeFilter{Comparison: Greater, Weight: 1}, LevelPackages)
+	assert.True(t, errors.Is(err, ErrUnsupportedEdgeFilter))
+}
+
+func TestComparisonCompare(t *testing.T) {
+	t.Parallel()
+
+	for comparison, expected := range map[Comparison][3]bool{
+		Equal:          {false, true, false},
+		Greater:        {false, false, true},
+		GreaterOrEqual: {false, true, true},
+		Less:           {true, false, false},
+		LessOrEqual:    {true, true, false},
+	} {
+		for idx, value := range []int{1, 2, 3} {
+			assert.Equal(t, expected[idx], comparison.Compare(value, 2), "%d%s2", value, comparison)
+		}
+	}
+}
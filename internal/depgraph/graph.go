@@ -13,8 +13,35 @@ type DepGraph struct {
 	Graph *graph.HierarchicalDigraph
 
 	replaces map[string]string
+	stdLib   bool
 }
 
+// GraphOptions allows tuning the construction of a DepGraph by GetGraph.
+type GraphOptions struct {
+	// Include the standard library packages imported by the dependency graph as nodes of a
+	// synthetic 'std' module. These nodes are only matched by queries that explicitly target them.
+	StdLib bool
+	// Retrieve the latest available version of each module so that their update backlog is known.
+	// This requires network access.
+	Updates bool
+}
+
+// StdLibModule is the name of the synthetic module to which standard library packages belong.
+const StdLibModule = "std"
+
+// DataAnnotated is implemented by nodes that can provide properties for the formats that are meant
+// to be consumed by graph analysis tools instead of being rendered directly.
+type DataAnnotated interface {
+	NodeData() map[string]interface{}
+	EdgeData(target graph.Node) map[string]interface{}
+}
+
+var (
+	_ DataAnnotated = &Module{}
+	_ DataAnnotated = &Package{}
+	_ DataAnnotated = &Collapsed{}
+)
+
 type Level uint8
 
 const (
@@ -58,3 +85,53 @@ func (g *DepGraph) AddModule(module *modules.ModuleInfo) *Module {
 	}
 	return newModule
 }
+
+// Clone returns a deep copy of the dependency graph. The copy can be modified, for example by
+// applying a query to it, without affecting the original.
+func (g *DepGraph) Clone() (*DepGraph, error) {
+	c, err := g.Graph.Clone()
+	if err != nil {
+		return nil, err
+	}
+	return g.withGraph(c), nil
+}
+
+// Subgraph returns a new dependency graph induced by the nodes with the specified names at the
+// given level. Modules of selected packages and packages of selected modules are retained as well.
+// The original graph is left untouched.
+func (g *DepGraph) Subgraph(names []string, level Level) (*DepGraph, error) {
+	hashes := make([]string, 0, len(names))
+	for _, name := range names {
+		hashes = append(hashes, nodeHash(name, level))
+	}
+	s, err := g.Graph.Subgraph(hashes)
+	if err != nil {
+		return nil, err
+	}
+	return g.withGraph(s), nil
+}
+
+func (g *DepGraph) withGraph(hg *graph.HierarchicalDigraph) *DepGraph {
+	c := &DepGraph{
+		Path:     g.Path,
+		Graph:    hg,
+		replaces: make(map[string]string, len(g.replaces)),
+		stdLib:   g.stdLib,
+	}
+	for k, v := range g.replaces {
+		c.replaces[k] = v
+	}
+	if g.Main != nil {
+		if main, err := hg.GetNode(g.Main.Hash()); err == nil {
+			c.Main = main.(*Module)
+		}
+	}
+	return c
+}
+
+func nodeHash(name string, level Level) string {
+	if level == LevelPackages {
+		return packageHash(name)
+	}
+	return moduleHash(name)
+}
@@ -0,0 +1,125 @@
+package depgraph
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v3"
+	"go.uber.org/zap"
+
+	"github.com/Helcaraxan/gomod/internal/graph"
+	"github.com/Helcaraxan/gomod/internal/logger"
+)
+
+var ErrInvalidTestPattern = errors.New("invalid test pattern")
+
+// DefaultTestPatterns match the modules and packages of widely used testing and mocking libraries as
+// well as the conventional names of packages with test helpers.
+var DefaultTestPatterns = []string{
+	"github.com/stretchr/testify",
+	"github.com/golang/mock",
+	"go.uber.org/mock",
+	"github.com/onsi/ginkgo",
+	"github.com/onsi/gomega",
+	"gotest.tools",
+	"**/testutil",
+	"**/testutils",
+	"**/testhelpers",
+	"**/mocks",
+}
+
+// TestLeak is a test-oriented package that is imported, directly or indirectly, by the non-test code
+// of the main module.
+type TestLeak struct {
+	Package string
+	Module  string
+	Version string
+	// Shortest chain of non-test imports leading from a package of the main module to the leaked one.
+	Chain []string
+}
+
+// TestLeaks are the test-oriented packages that are imported by non-test code.
+type TestLeaks []TestLeak
+
+// FindTestLeaks returns the packages that match one of the given patterns, either via their own
+// path or that of their module, and that are reachable from the main module through non-test imports
+// only. Packages of the main module that match a pattern are not considered as non-test code
+// themselves, so they are only reported if they are imported by other non-test code. The result is
+// ordered by package path.
+func (g *DepGraph) FindTestLeaks(dl *logger.Builder, patterns []string) (TestLeaks, error) {
+	log := dl.Domain(logger.GraphDomain)
+
+	for _, pattern := range patterns {
+		if _, err := doublestar.Match(pattern, pattern); err != nil {
+			log.Error("Invalid test pattern.", zap.String("pattern", pattern), zap.Error(err))
+			return nil, fmt.Errorf("%q: %w", pattern, ErrInvalidTestPattern)
+		}
+	}
+	isTestOriented := func(node graph.Node) bool {
+		for _, pattern := range patterns {
+			if match, _ := doublestar.Match(pattern, node.Name()); match {
+				return true
+			}
+			if match, _ := doublestar.Match(pattern, node.Parent().Name()); match {
+				return true
+			}
+		}
+		return false
+	}
+
+	var sources []graph.Node
+	for _, pkg := range g.Main.packages.List() {
+		if !strings.HasSuffix(pkg.(*Package).Info.Name, "_test") && !isTestOriented(pkg) {
+			sources = append(sources, pkg)
+		}
+	}
+	sort.Slice(sources, func(i int, j int) bool { return sources[i].Name() < sources[j].Name() })
+	chains := newDependencyChains(sources, true)
+
+	var leaks TestLeaks
+	for _, node := range g.Graph.GetLevel(int(LevelPackages)).List() {
+		if !isTestOriented(node) {
+			continue
+		}
+		chain, ok := chains.chain(node.Name())
+		if !ok {
+			continue
+		}
+		module := node.Parent().(*Module)
+		log.Debug("Found test-oriented package imported by non-test code.", zap.String("package", node.Name()), zap.Strings("chain", chain))
+		leaks = append(leaks, TestLeak{
+			Package: node.Name(),
+			Module:  module.Name(),
+			Version: module.SelectedVersion(),
+			Chain:   chain,
+		})
+	}
+	sort.Slice(leaks, func(i int, j int) bool { return leaks[i].Package < leaks[j].Package })
+	return leaks, nil
+}
+
+// Print lists the leaked packages together with the chain of non-test imports through which each of
+// them is reached.
+func (l TestLeaks) Print(w io.Writer) error {
+	if len(l) == 0 {
+		_, err := fmt.Fprintln(w, "No test-oriented packages are imported by non-test code.")
+		return err
+	}
+
+	var lines []string
+	for _, leak := range l {
+		module := leak.Module
+		if leak.Version != "" {
+			module += "@" + leak.Version
+		}
+		lines = append(lines, fmt.Sprintf("LEAK %s (%s)", leak.Package, module))
+		if len(leak.Chain) > 1 {
+			lines = append(lines, "  "+strings.Join(leak.Chain, " -> "))
+		}
+	}
+	_, err := fmt.Fprintln(w, strings.Join(lines, "\n"))
+	return err
+}
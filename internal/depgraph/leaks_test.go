@@ -0,0 +1,120 @@
+package depgraph
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Helcaraxan/gomod/internal/testutil"
+)
+
+func TestFindTestLeaks(t *testing.T) {
+	cwd, err := os.Getwd()
+	require.NoError(t, err)
+
+	testcases := map[string]struct {
+		patterns      []string
+		expected      TestLeaks
+		expectedError error
+	}{
+		"Defaults": {
+			patterns: DefaultTestPatterns,
+		},
+		"TestHelpersAreProduction": {
+			patterns: []string{"github.com/stretchr/testify"},
+			expected: TestLeaks{
+				{
+					Package: "github.com/stretchr/testify/assert",
+					Module:  "github.com/stretchr/testify",
+					Version: "v1.6.1",
+					Chain:   []string{"ourorg.com/svc/internal/testhelpers", "github.com/stretchr/testify/assert"},
+				},
+			},
+		},
+		"MainModulePackage": {
+			patterns: []string{"ourorg.com/svc/storage", "example.com/db"},
+			expected: TestLeaks{
+				{
+					Package: "example.com/db",
+					Module:  "example.com/db",
+					Version: "v1.0.0",
+					Chain:   []string{"ourorg.com/svc/internal/repo", "ourorg.com/svc/storage", "example.com/db"},
+				},
+				{
+					Package: "ourorg.com/svc/storage",
+					Module:  "ourorg.com/svc",
+					Chain:   []string{"ourorg.com/svc/internal/repo", "ourorg.com/svc/storage"},
+				},
+			},
+		},
+		"InvalidPattern": {
+			patterns:      []string{"example.com/[a"},
+			expectedError: ErrInvalidTestPattern,
+		},
+	}
+
+	for name := range testcases {
+		testcase := testcases[name]
+		t.Run(name, func(t *testing.T) {
+			testDir := testutil.SetupTestModule(t, filepath.Join(cwd, "testdata", "Check.yaml"), &testutil.GraphTestDefinition{})
+
+			log := testutil.TestLogger(t)
+			g, err := GetGraph(log, testDir, nil)
+			require.NoError(t, err)
+
+			leaks, err := g.FindTestLeaks(log, testcase.patterns)
+			if testcase.expectedError != nil {
+				assert.True(t, errors.Is(err, testcase.expectedError))
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, testcase.expected, leaks)
+		})
+	}
+}
+
+func TestTestLeaksPrint(t *testing.T) {
+	testcases := map[string]struct {
+		leaks    TestLeaks
+		expected string
+	}{
+		"None": {
+			expected: "No test-oriented packages are imported by non-test code.\n",
+		},
+		"Leaks": {
+			leaks: TestLeaks{
+				{
+					Package: "github.com/stretchr/testify/assert",
+					Module:  "github.com/stretchr/testify",
+					Version: "v1.6.1",
+					Chain:   []string{"ourorg.com/svc/cmd", "ourorg.com/svc/internal/testhelpers", "github.com/stretchr/testify/assert"},
+				},
+				{
+					Package: "ourorg.com/svc/internal/testhelpers",
+					Module:  "ourorg.com/svc",
+					Chain:   []string{"ourorg.com/svc/internal/testhelpers"},
+				},
+			},
+			expected: `LEAK github.com/stretchr/testify/assert (github.com/stretchr/testify@v1.6.1)
+  ourorg.com/svc/cmd -> ourorg.com/svc/internal/testhelpers -> github.com/stretchr/testify/assert
+LEAK ourorg.com/svc/internal/testhelpers (ourorg.com/svc)
+`,
+		},
+	}
+
+	for name := range testcases {
+		testcase := testcases[name]
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var out bytes.Buffer
+			require.NoError(t, testcase.leaks.Print(&out))
+			assert.Equal(t, testcase.expected, out.String())
+		})
+	}
+}
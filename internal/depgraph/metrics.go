@@ -0,0 +1,43 @@
+package depgraph
+
+import (
+	"math"
+	"time"
+
+	"github.com/Helcaraxan/gomod/internal/graph"
+	"github.com/Helcaraxan/gomod/internal/logger"
+)
+
+// nodeMetric computes a numerical property of a node in the dependency graph. Higher values are
+// ranked first when selecting nodes based on a metric.
+type nodeMetric func(log *logger.Logger, n graph.Node) int64
+
+var nodeMetrics = map[string]nodeMetric{
+	"in_degree":  func(_ *logger.Logger, n graph.Node) int64 { return int64(n.Predecessors().Len()) },
+	"out_degree": func(_ *logger.Logger, n graph.Node) int64 { return int64(n.Successors().Len()) },
+	"rdeps": func(log *logger.Logger, n graph.Node) int64 {
+		return int64(len(traverse(log, n, backwards, math.MaxInt64)) - 1)
+	},
+	"deps": func(log *logger.Logger, n graph.Node) int64 {
+		return int64(len(traverse(log, n, forwards, math.MaxInt64)) - 1)
+	},
+	"packages": func(_ *logger.Logger, n graph.Node) int64 {
+		if n.Children() == nil {
+			return 0
+		}
+		return int64(n.Children().Len())
+	},
+	"age": func(_ *logger.Logger, n graph.Node) int64 {
+		m, ok := n.(*Module)
+		if !ok {
+			m = n.Parent().(*Module)
+		}
+		t := m.Timestamp()
+		if t == nil {
+			return -1
+		}
+		return int64(time.Since(*t))
+	},
+}
+
+const defaultRankingMetric = "in_degree"
@@ -2,6 +2,7 @@ package depgraph
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/Helcaraxan/gomod/internal/graph"
@@ -72,6 +73,20 @@ func (m *Module) Timestamp() *time.Time {
 	return m.Info.Time
 }
 
+// UpdateBacklog returns the time between the creation of the version at which this dependency is
+// used and that of its latest available update. The backlog is only known if the graph was created
+// with the Updates option and is zero for modules without an available update.
+func (m *Module) UpdateBacklog() (time.Duration, bool) {
+	t := m.Timestamp()
+	if t == nil {
+		return 0, false
+	}
+	if m.Info.Update == nil || m.Info.Update.Time == nil || !m.Info.Update.Time.After(*t) {
+		return 0, true
+	}
+	return m.Info.Update.Time.Sub(*t), true
+}
+
 func (m *Module) Parent() graph.Node {
 	return nil
 }
@@ -88,11 +103,24 @@ func (m *Module) Children() *graph.NodeRefs {
 	return &m.packages
 }
 
-func (m *Module) NodeAttributes(annotate bool) []string {
+func (m *Module) Copy(graph.Node) graph.Node {
+	c := NewModule(m.Info)
+	for k, v := range m.Indirects {
+		c.Indirects[k] = v
+	}
+	for k, v := range m.VersionConstraints {
+		c.VersionConstraints[k] = v
+	}
+	c.isNonTestDependency = m.isNonTestDependency
+	return c
+}
+
+func (m *Module) NodeAttributes(annotate bool, highlight Highlight) []string {
 	var annotations []string
 
-	text, background := hashToColourHSV(m.Hash(), m.isTestDependency())
+	text, background := hashToColourHSV(m.Hash(), m.isTestDependency(), highlight)
 	annotations = append(annotations, fmt.Sprintf(`fontcolor="%s"`, text), fmt.Sprintf(`fillcolor="%s"`, background))
+	annotations = append(annotations, nodeHighlightAttributes(highlight)...)
 
 	if annotate && m.SelectedVersion() != "" {
 		var replacement string
@@ -107,22 +135,92 @@ func (m *Module) NodeAttributes(annotate bool) []string {
 	return annotations
 }
 
-func (m *Module) EdgeAttributes(target graph.Node, annotate bool) []string {
-	targetModule := target.(*Module)
-
+func (m *Module) EdgeAttributes(target graph.Node, annotate bool, weights bool, highlight Highlight) []string {
 	var annotations []string
 	if m.Indirects[target.Name()] {
 		annotations = append(annotations, "style=dashed") //nolint:misspell
 	}
-	if target.(testAnnotated).isTestDependency() {
+	if highlight != HighlightNone {
+		annotations = append(annotations, edgeHighlightAttributes(highlight)...)
+	} else if target.(testAnnotated).isTestDependency() {
 		annotations = append(annotations, "color=lightblue") //nolint:misspell
 	}
-	if c, ok := m.VersionConstraints[targetModule.Hash()]; ok && annotate {
-		annotations = append(annotations, fmt.Sprintf("label=<<font point-size=\"10\">%s</font>>", c.Target))
+	if _, ok := mergedEdgeCount(m, target); ok {
+		return append(annotations, mergedEdgeAttributes(m, target)...)
+	}
+
+	weight := m.EdgeWeight(target)
+	if highlight != Highlighted {
+		annotations = append(annotations, edgeWeightAttributes(weight)...)
+	}
+
+	var label []string
+	if c, ok := m.VersionConstraints[target.Hash()]; ok && annotate {
+		label = append(label, c.Target)
+	}
+	if weights && weight > 0 {
+		imports := "imports"
+		if weight == 1 {
+			imports = "import"
+		}
+		label = append(label, fmt.Sprintf("%d %s", weight, imports))
+	}
+	if len(label) > 0 {
+		annotations = append(annotations, fmt.Sprintf("label=<<font point-size=\"10\">%s</font>>", strings.Join(label, "<br />")))
 	}
 	return annotations
 }
 
+// EdgeWeight returns the number of imports of the target module's packages by packages of this
+// module. A dependency that is only declared via the module's requirements has a weight of zero.
+func (m *Module) EdgeWeight(target graph.Node) int {
+	_, weight := m.successors.Get(target.Hash())
+	if _, ok := m.VersionConstraints[target.Hash()]; ok && weight > 0 {
+		// The requirement itself contributes to the weight of the edge.
+		weight--
+	}
+	return weight
+}
+
+// NodeData returns the properties of the module that are exported to formats meant to be processed
+// by graph analysis tools. Properties that are not known for this module are omitted.
+func (m *Module) NodeData() map[string]interface{} {
+	data := map[string]interface{}{
+		"path":          m.Name(),
+		"test_only":     m.isTestDependency(),
+		"indirect":      m.Info.Indirect,
+		"package_count": m.packages.Len(),
+	}
+	if v := m.SelectedVersion(); v != "" {
+		data["version"] = v
+	}
+	if m.Info.Replace != nil {
+		data["replacement"] = m.Info.Replace.Path
+	}
+	if t := m.Timestamp(); t != nil {
+		data["timestamp"] = t.UTC().Format(time.RFC3339)
+	}
+	return data
+}
+
+// EdgeData returns the properties of the dependency on the target module that are exported to
+// formats meant to be processed by graph analysis tools.
+func (m *Module) EdgeData(target graph.Node) map[string]interface{} {
+	data := map[string]interface{}{
+		"indirect":  m.Indirects[target.Name()],
+		"test_only": target.(testAnnotated).isTestDependency(),
+	}
+	if c, ok := m.VersionConstraints[target.Hash()]; ok {
+		data["version_constraint"] = c.Target
+	}
+	if count, ok := mergedEdgeCount(m, target); ok {
+		data["edge_count"] = count
+	} else {
+		data["weight"] = m.EdgeWeight(target)
+	}
+	return data
+}
+
 var _ testAnnotated = &Module{}
 
 func (m *Module) isTestDependency() bool {
@@ -1,7 +1,10 @@
 package depgraph
 
 import (
+	"bytes"
 	"fmt"
+	"io/ioutil"
+	"path/filepath"
 
 	"github.com/Helcaraxan/gomod/internal/graph"
 	"github.com/Helcaraxan/gomod/internal/modules"
@@ -59,17 +62,73 @@ func (p *Package) Parent() graph.Node {
 	return p.parent
 }
 
-func (p *Package) NodeAttributes(annotate bool) []string {
+func (p *Package) Copy(parent graph.Node) graph.Node {
+	c := NewPackage(p.Info, parent.(*Module))
+	c.isNonTestDependency = p.isNonTestDependency
+	return c
+}
+
+// FileCount returns the number of Go files that are compiled as part of the package. Test files are
+// not included.
+func (p *Package) FileCount() int {
+	return len(p.Info.GoFiles) + len(p.Info.CgoFiles)
+}
+
+// LineCount returns the number of lines of the Go files that are compiled as part of the package,
+// as read from the package's directory. Test files are not included.
+func (p *Package) LineCount() (int, error) {
+	if p.Info.Dir == "" && p.FileCount() > 0 {
+		return 0, fmt.Errorf("no source directory known for package %q", p.Name())
+	}
+
+	var count int
+	for _, files := range [][]string{p.Info.GoFiles, p.Info.CgoFiles} {
+		for _, file := range files {
+			content, err := ioutil.ReadFile(filepath.Join(p.Info.Dir, file))
+			if err != nil {
+				return 0, err
+			}
+			count += bytes.Count(content, []byte("\n"))
+		}
+	}
+	return count, nil
+}
+
+func (p *Package) NodeAttributes(annotate bool, highlight Highlight) []string {
 	var annotations []string
 
-	text, background := hashToColourHSV(p.Parent().Hash(), p.isTestDependency())
+	text, background := hashToColourHSV(p.Parent().Hash(), p.isTestDependency(), highlight)
 	annotations = append(annotations, fmt.Sprintf(`fontcolor="%s"`, text), fmt.Sprintf(`fillcolor="%s"`, background))
+	annotations = append(annotations, nodeHighlightAttributes(highlight)...)
 
 	return annotations
 }
 
-func (p *Package) EdgeAttributes(target graph.Node, annotate bool) []string {
-	return nil
+func (p *Package) EdgeAttributes(target graph.Node, annotate bool, weights bool, highlight Highlight) []string {
+	return append(edgeHighlightAttributes(highlight), mergedEdgeAttributes(p, target)...)
+}
+
+// NodeData returns the properties of the package that are exported to formats meant to be
+// processed by graph analysis tools. Module-level properties are those of the parent module.
+func (p *Package) NodeData() map[string]interface{} {
+	data := p.parent.NodeData()
+	delete(data, "package_count")
+	data["path"] = p.Name()
+	data["module"] = p.parent.Name()
+	data["test_only"] = p.isTestDependency()
+	return data
+}
+
+// EdgeData returns the properties of the import of the target package that are exported to formats
+// meant to be processed by graph analysis tools.
+func (p *Package) EdgeData(target graph.Node) map[string]interface{} {
+	data := map[string]interface{}{
+		"test_only": target.(testAnnotated).isTestDependency(),
+	}
+	if count, ok := mergedEdgeCount(p, target); ok {
+		data["edge_count"] = count
+	}
+	return data
 }
 
 func (p *Package) isTestDependency() bool {
@@ -14,20 +14,27 @@ import (
 var depRE = regexp.MustCompile(`^([^@\s]+)@?([^@\s]+)? ([^@\s]+)@([^@\s]+)$`)
 
 // GetGraph will return the dependency graph for the Go module that can be found at the specified
-// path.
-func GetGraph(dl *logger.Builder, path string) (*DepGraph, error) {
+// path. The options may be nil in which case the defaults are used.
+func GetGraph(dl *logger.Builder, path string, opts *GraphOptions) (*DepGraph, error) {
 	if dl == nil {
 		dl = logger.NewBuilder(os.Stderr)
 	}
 	log := dl.Domain(logger.GraphDomain)
 	log.Debug("Creating dependency graph.")
 
-	mainModule, moduleInfo, err := modules.GetDependencies(dl.Domain(logger.ModuleInfoDomain), path)
+	getDependencies := modules.GetDependencies
+	if opts != nil && opts.Updates {
+		getDependencies = modules.GetDependenciesWithUpdates
+	}
+	mainModule, moduleInfo, err := getDependencies(dl.Domain(logger.ModuleInfoDomain), path)
 	if err != nil {
 		return nil, err
 	}
 
 	g := NewGraph(log, path, mainModule)
+	if opts != nil {
+		g.stdLib = opts.StdLib
+	}
 	for _, module := range moduleInfo {
 		g.AddModule(module)
 	}
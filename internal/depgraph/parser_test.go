@@ -0,0 +1,114 @@
+package depgraph
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Helcaraxan/gomod/internal/query"
+	"github.com/Helcaraxan/gomod/internal/testutil"
+)
+
+func TestStdLibPackages(t *testing.T) {
+	cwd, err := os.Getwd()
+	require.NoError(t, err)
+
+	nodeNames := func(g *DepGraph, level Level) []string {
+		var names []string
+		for _, n := range g.Graph.GetLevel(int(level)).List() {
+			names = append(names, n.Name())
+		}
+		return names
+	}
+
+	t.Run("Disabled", func(t *testing.T) {
+		testDir := testutil.SetupTestModule(t, filepath.Join(cwd, "testdata", "StdLib.yaml"), &testutil.GraphTestDefinition{})
+
+		g, err := GetGraph(testutil.TestLogger(t), testDir, nil)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"example.com/dep1", "test"}, nodeNames(g, LevelModules))
+		assert.Equal(t, []string{"example.com/dep1", "test"}, nodeNames(g, LevelPackages))
+	})
+
+	t.Run("Enabled", func(t *testing.T) {
+		testDir := testutil.SetupTestModule(t, filepath.Join(cwd, "testdata", "StdLib.yaml"), &testutil.GraphTestDefinition{})
+
+		log := testutil.TestLogger(t)
+		g, err := GetGraph(log, testDir, &GraphOptions{StdLib: true})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"example.com/dep1", StdLibModule, "test"}, nodeNames(g, LevelModules))
+		assert.Equal(t, []string{"example.com/dep1", "fmt", "net", "net/http", "test", "testing", "unsafe"}, nodeNames(g, LevelPackages))
+
+		testingPkg, err := g.Graph.GetNode(packageHash("testing"))
+		require.NoError(t, err)
+		assert.True(t, testingPkg.(testAnnotated).isTestDependency())
+		unsafe, err := g.Graph.GetNode(packageHash("unsafe"))
+		require.NoError(t, err)
+		assert.False(t, unsafe.(testAnnotated).isTestDependency())
+
+		testcases := map[string]struct {
+			query    string
+			level    Level
+			expected nodeSet
+		}{
+			"AllNonStd": {
+				query:    "**",
+				level:    LevelPackages,
+				expected: nodeSet{"example.com/dep1": true, "test": true},
+			},
+			"StdPackages": {
+				query:    "std",
+				level:    LevelPackages,
+				expected: nodeSet{"fmt": true, "net": true, "net/http": true, "unsafe": true},
+			},
+			"StdPackagesWithTest": {
+				query:    "std:test",
+				level:    LevelPackages,
+				expected: nodeSet{"fmt": true, "net": true, "net/http": true, "testing": true, "unsafe": true},
+			},
+			"StdPattern": {
+				query:    "std:net/**",
+				level:    LevelPackages,
+				expected: nodeSet{"net": true, "net/http": true},
+			},
+			"StdPatternEllipsis": {
+				query:    "std:net/...",
+				level:    LevelPackages,
+				expected: nodeSet{"net": true, "net/http": true},
+			},
+			"StdPatternEllipsisModule": {
+				query:    "std:net/...",
+				level:    LevelModules,
+				expected: nodeSet{StdLibModule: true},
+			},
+			"StdPatternEllipsisRootOnly": {
+				query:    "std:fmt/...",
+				level:    LevelPackages,
+				expected: nodeSet{"fmt": true},
+			},
+			"StdModule": {
+				query:    "std:unsafe",
+				level:    LevelModules,
+				expected: nodeSet{StdLibModule: true},
+			},
+			"UnsafeUsers": {
+				query:    "rdeps(std:unsafe, 1) - std",
+				level:    LevelPackages,
+				expected: nodeSet{"example.com/dep1": true},
+			},
+		}
+		for name := range testcases {
+			testcase := testcases[name]
+			t.Run(name, func(t *testing.T) {
+				q, err := query.Parse(log, testcase.query)
+				require.NoError(t, err)
+				set, err := g.computeSet(log.Log(), q, testcase.level)
+				require.NoError(t, err)
+				assert.Equal(t, testcase.expected, set)
+			})
+		}
+	})
+}
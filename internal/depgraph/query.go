@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"sort"
 	"strings"
 
 	"github.com/bmatcuk/doublestar/v3"
@@ -50,6 +51,23 @@ func (g *DepGraph) ApplyQuery(dl *logger.Builder, q query.Expr, level Level) err
 	return nil
 }
 
+// Query returns a new graph that only contains the nodes selected by the given query. Contrary to
+// ApplyQuery the receiver is not modified so that it can be queried again.
+func (g *DepGraph) Query(dl *logger.Builder, q query.Expr, level Level) (*DepGraph, error) {
+	log := dl.Domain(logger.QueryDomain)
+
+	targetSet, err := g.computeSet(log, q, level)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(targetSet))
+	for name := range targetSet {
+		names = append(names, name)
+	}
+	return g.Subgraph(names, level)
+}
+
 func (ns nodeSet) String() string {
 	var s []string
 	for n := range ns {
@@ -88,7 +106,17 @@ func (g *DepGraph) computeSet(log *logger.Logger, expr query.Expr, level Level)
 func (g *DepGraph) computeSetNameMatch(log *logger.Logger, expr *query.ExprString, level Level) (nodeSet, error) {
 	var withTestDeps bool
 
+	// Standard library nodes are only matched by queries that explicitly target them via a 'std' or
+	// 'std:<pattern>' expression. The pattern is then matched against standard library packages.
 	parts := strings.Split(expr.Value(), ":")
+	stdLib := parts[0] == StdLibModule
+	if stdLib {
+		parts = parts[1:]
+		if len(parts) == 0 || parts[0] == "test" {
+			parts = append([]string{"**"}, parts...)
+		}
+	}
+
 	if len(parts) > 2 {
 		return nil, &queryErr{
 			err:  fmt.Sprintf("expression contains more than one ':' character"),
@@ -106,18 +134,23 @@ func (g *DepGraph) computeSetNameMatch(log *logger.Logger, expr *query.ExprStrin
 		}
 	}
 
-	q := parts[0]
-	if _, err := doublestar.Match(q, ""); err != nil {
-		return nil, &queryErr{
-			err:  fmt.Sprintf("invalid query: %v", err),
-			expr: expr,
+	patterns := []string{parts[0]}
+	if stdLib {
+		patterns = stdLibPatterns(parts[0])
+	}
+	for _, pattern := range patterns {
+		if _, err := doublestar.Match(pattern, ""); err != nil {
+			return nil, &queryErr{
+				err:  fmt.Sprintf("invalid query: %v", err),
+				expr: expr,
+			}
 		}
 	}
 
 	set := nodeSet{}
 	for _, node := range g.Graph.GetLevel(int(level)).List() {
 		p, ok := node.(*Package)
-		matches, _ := doublestar.Match(q, node.Name())
+		matches := matchNodeName(patterns, node, stdLib)
 		switch {
 		case !withTestDeps && ((ok && strings.HasSuffix(p.Info.Name, "_test")) || node.(testAnnotated).isTestDependency()):
 			log.Debug("Discarded node as it is a test dependency.", zap.String("name", node.Name()))
@@ -135,6 +168,45 @@ func (g *DepGraph) computeSetNameMatch(log *logger.Logger, expr *query.ExprStrin
 	return set, nil
 }
 
+// stdLibPatterns converts a standard library pattern ending in '/...' or '/**' into patterns that
+// match both the packages below a path and the package at the path itself, as with 'go list std'.
+func stdLibPatterns(pattern string) []string {
+	for _, suffix := range []string{"/...", "/**"} {
+		if root := strings.TrimSuffix(pattern, suffix); root != pattern && root != "" {
+			return []string{root, root + "/**"}
+		}
+	}
+	return []string{pattern}
+}
+
+func matchNodeName(patterns []string, node graph.Node, stdLib bool) bool {
+	if isStdLibNode(node) != stdLib {
+		return false
+	}
+	names := []string{node.Name()}
+	if stdLib && node.Children() != nil {
+		// The synthetic standard library module matches if any of its packages does.
+		for _, child := range node.Children().List() {
+			names = append(names, child.Name())
+		}
+	}
+	for _, pattern := range patterns {
+		for _, name := range names {
+			if matches, _ := doublestar.Match(pattern, name); matches {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func isStdLibNode(node graph.Node) bool {
+	if p, ok := node.(*Package); ok {
+		node = p.Parent()
+	}
+	return node.Name() == StdLibModule
+}
+
 func (g *DepGraph) computeSetBinaryOp(log *logger.Logger, expr query.BinaryExpr, level Level) (set nodeSet, err error) {
 	defer func() {
 		if err == nil && len(set) == 0 {
@@ -175,6 +247,14 @@ func (g *DepGraph) computeSetFunc(log *logger.Logger, expr query.FuncExpr, level
 		return g.computeSetGraphTraversal(log, expr, backwards, level)
 	case "shared":
 		return g.sharedFunc(log, expr, level)
+	case "top":
+		return g.topFunc(log, expr, level)
+	case "leaves":
+		return g.frontierFunc(log, expr, forwards, level)
+	case "roots":
+		return g.frontierFunc(log, expr, backwards, level)
+	case "depth":
+		return g.depthFunc(log, expr, level)
 	default:
 		return nil, &queryErr{
 			err:  fmt.Sprintf("unknown function %q", expr.Name()),
@@ -217,6 +297,25 @@ func (g *DepGraph) computeSetGraphTraversal(log *logger.Logger, expr query.FuncE
 	}
 	log.Debug("Maximum depths for traversals set.", zap.Int("maxDepth", maxDepth))
 
+	sources, err := g.computeSet(log, args.Args()[0], level)
+	if err != nil {
+		return nil, err
+	}
+
+	set := nodeSet{}
+	for src := range sources {
+		node, _ := g.Graph.GetNode(nodeHash(src, level))
+		for name := range traverse(log, node, direction, maxDepth) {
+			set[name] = true
+		}
+	}
+	return set, nil
+}
+
+// traverse performs a breadth-first traversal of the graph in the given direction, starting at the
+// specified node and stopping at the given maximum depth. It returns the distance at which each of
+// the reached nodes was first encountered, the starting node itself being at distance 0.
+func traverse(log *logger.Logger, start graph.Node, direction traversalDirection, maxDepth int) map[string]int {
 	var iterateFunc func(graph.Node) []graph.Node
 	switch direction {
 	case forwards:
@@ -225,52 +324,29 @@ func (g *DepGraph) computeSetGraphTraversal(log *logger.Logger, expr query.FuncE
 		iterateFunc = func(n graph.Node) []graph.Node { return n.Predecessors().List() }
 	}
 
-	sources, err := g.computeSet(log, args.Args()[0], level)
-	if err != nil {
-		return nil, err
-	}
+	depths := map[string]int{start.Name(): 0}
+	todo := []graph.Node{start}
+	for len(todo) > 0 {
+		next := todo[0]
+		todo = todo[1:]
 
-	set := nodeSet{}
-	for src := range sources {
-		var h string
-		switch level {
-		case LevelModules:
-			h = moduleHash(src)
-		case LevelPackages:
-			h = packageHash(src)
-		}
-		node, _ := g.Graph.GetNode(h)
-
-		seen := nodeSet{}
-		todo := []struct {
-			n graph.Node
-			d int
-		}{{node, 0}}
-		for len(todo) > 0 {
-			next := todo[0]
-			todo = todo[1:]
-
-			set[next.n.Name()] = true
-			if next.d >= maxDepth {
-				log.Debug("Maximum depth reached.", zap.String("node", next.n.Name()))
+		d := depths[next.Name()]
+		if d >= maxDepth {
+			log.Debug("Maximum depth reached.", zap.String("node", next.Name()))
+			continue
+		}
+
+		for _, dep := range iterateFunc(next) {
+			if _, ok := depths[dep.Name()]; ok {
 				continue
 			}
 
-			for _, dep := range iterateFunc(next.n) {
-				if seen[dep.Name()] {
-					continue
-				}
-
-				log.Debug("Enqueing new node.", zap.String("node", dep.Name()), zap.Int("depth", next.d+1))
-				todo = append(todo, struct {
-					n graph.Node
-					d int
-				}{dep, next.d + 1})
-				seen[dep.Name()] = true
-			}
+			log.Debug("Enqueing new node.", zap.String("node", dep.Name()), zap.Int("depth", d+1))
+			todo = append(todo, dep)
+			depths[dep.Name()] = d + 1
 		}
 	}
-	return set, nil
+	return depths
 }
 
 func (g *DepGraph) sharedFunc(log *logger.Logger, expr query.FuncExpr, level Level) (nodeSet, error) {
@@ -287,26 +363,9 @@ func (g *DepGraph) sharedFunc(log *logger.Logger, expr query.FuncExpr, level Lev
 		return nil, err
 	}
 
-	nodesInSet := func(set nodeSet, list []graph.Node) int {
-		var c int
-		for _, n := range list {
-			if set[n.Name()] {
-				c++
-			}
-		}
-		return c
-	}
-
 	var todo []graph.Node
 	for src := range set {
-		var h string
-		switch level {
-		case LevelModules:
-			h = moduleHash(src)
-		case LevelPackages:
-			h = packageHash(src)
-		}
-		n, _ := g.Graph.GetNode(h)
+		n, _ := g.Graph.GetNode(nodeHash(src, level))
 
 		if nodesInSet(set, n.Successors().List()) == 0 && nodesInSet(set, n.Predecessors().List()) == 1 {
 			todo = append(todo, n)
@@ -332,6 +391,182 @@ func (g *DepGraph) sharedFunc(log *logger.Logger, expr query.FuncExpr, level Lev
 	return set, nil
 }
 
+func (g *DepGraph) frontierFunc(log *logger.Logger, expr query.FuncExpr, direction traversalDirection, level Level) (nodeSet, error) {
+	args := expr.Args()
+	if len(args.Args()) != 1 {
+		return nil, &queryErr{
+			err:  fmt.Sprintf("expected a single argument but received '%v'", len(args.Args())),
+			expr: expr,
+		}
+	}
+
+	set, err := g.computeSet(log, args.Args()[0], level)
+	if err != nil {
+		return nil, err
+	}
+
+	frontier := nodeSet{}
+	for name := range set {
+		n, _ := g.Graph.GetNode(nodeHash(name, level))
+
+		neighbours := n.Successors().List()
+		if direction == backwards {
+			neighbours = n.Predecessors().List()
+		}
+		if nodesInSet(set, neighbours) == 0 {
+			log.Debug("Found frontier node.", zap.String("name", name))
+			frontier[name] = true
+		}
+	}
+
+	if len(frontier) == 0 {
+		log.Warn("Empty query result.", zap.Stringer("query", expr))
+	}
+	return frontier, nil
+}
+
+func (g *DepGraph) depthFunc(log *logger.Logger, expr query.FuncExpr, level Level) (nodeSet, error) {
+	args := expr.Args()
+	if len(args.Args()) != 2 {
+		return nil, &queryErr{
+			err:  fmt.Sprintf("expected 2 arguments but received %d", len(args.Args())),
+			expr: expr,
+		}
+	}
+
+	v, ok := args.Args()[1].(*query.ExprInteger)
+	if !ok || v.Value() < 0 {
+		return nil, &queryErr{
+			err:  fmt.Sprintf("expected a non-negative integer as second argument but got '%v'", args.Args()[1]),
+			expr: expr,
+		}
+	}
+	depth := v.Value()
+
+	sources, err := g.computeSet(log, args.Args()[0], level)
+	if err != nil {
+		return nil, err
+	}
+
+	// A node's distance to the source set is the shortest distance to any of its members.
+	distances := map[string]int{}
+	for src := range sources {
+		node, _ := g.Graph.GetNode(nodeHash(src, level))
+		for name, d := range traverse(log, node, forwards, depth) {
+			if current, ok := distances[name]; !ok || d < current {
+				distances[name] = d
+			}
+		}
+	}
+
+	set := nodeSet{}
+	for name, d := range distances {
+		if d == depth {
+			set[name] = true
+		}
+	}
+
+	if len(set) == 0 {
+		log.Warn("Empty query result.", zap.Stringer("query", expr))
+	}
+	return set, nil
+}
+
+func nodesInSet(set nodeSet, list []graph.Node) int {
+	var c int
+	for _, n := range list {
+		if set[n.Name()] {
+			c++
+		}
+	}
+	return c
+}
+
+func (g *DepGraph) topFunc(log *logger.Logger, expr query.FuncExpr, level Level) (nodeSet, error) {
+	var positional []query.Expr
+	metricName := defaultRankingMetric
+	for _, arg := range expr.Args().Args() {
+		kw, ok := arg.(query.KeywordArgExpr)
+		if !ok {
+			positional = append(positional, arg)
+			continue
+		}
+		if kw.Key() != "by" {
+			return nil, &queryErr{
+				err:  fmt.Sprintf("unknown keyword argument %q", kw.Key()),
+				expr: expr,
+			}
+		}
+		v, ok := kw.Value().(*query.ExprString)
+		if !ok {
+			return nil, &queryErr{
+				err:  fmt.Sprintf("expected a metric name as value for 'by' but got '%v'", kw.Value()),
+				expr: expr,
+			}
+		}
+		metricName = v.Value()
+	}
+
+	if len(positional) != 2 {
+		return nil, &queryErr{
+			err:  fmt.Sprintf("expected a set and an integer as arguments but received %d arguments", len(positional)),
+			expr: expr,
+		}
+	}
+	count, ok := positional[1].(*query.ExprInteger)
+	if !ok || count.Value() < 0 {
+		return nil, &queryErr{
+			err:  fmt.Sprintf("expected a non-negative integer as second argument but got '%v'", positional[1]),
+			expr: expr,
+		}
+	}
+
+	metric, ok := nodeMetrics[metricName]
+	if !ok {
+		return nil, &queryErr{
+			err:  fmt.Sprintf("unknown metric %q", metricName),
+			expr: expr,
+		}
+	} else if metricName == "packages" && level != LevelModules {
+		return nil, &queryErr{
+			err:  "the 'packages' metric is only available at module level",
+			expr: expr,
+		}
+	}
+
+	set, err := g.computeSet(log, positional[0], level)
+	if err != nil {
+		return nil, err
+	}
+
+	type rankedNode struct {
+		name  string
+		value int64
+	}
+	ranking := make([]rankedNode, 0, len(set))
+	for name := range set {
+		n, _ := g.Graph.GetNode(nodeHash(name, level))
+		ranking = append(ranking, rankedNode{name: name, value: metric(log, n)})
+	}
+	sort.Slice(ranking, func(i int, j int) bool {
+		if ranking[i].value != ranking[j].value {
+			return ranking[i].value > ranking[j].value
+		}
+		return ranking[i].name < ranking[j].name
+	})
+
+	top := nodeSet{}
+	for idx := 0; idx < len(ranking) && idx < count.Value(); idx++ {
+		log.Debug("Selecting ranked node.", zap.String("name", ranking[idx].name), zap.String("metric", metricName), zap.Int64("value", ranking[idx].value))
+		top[ranking[idx].name] = true
+	}
+
+	if len(top) == 0 {
+		log.Warn("Empty query result.", zap.Stringer("query", expr))
+	}
+	return top, nil
+}
+
 type testAnnotated interface {
 	isTestDependency() bool
 }
@@ -339,6 +574,7 @@ type testAnnotated interface {
 var (
 	_ testAnnotated = &Module{}
 	_ testAnnotated = &Package{}
+	_ testAnnotated = &Collapsed{}
 )
 
 type nodeSet map[string]bool
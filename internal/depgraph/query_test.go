@@ -2,7 +2,10 @@ package depgraph
 
 import (
 	"errors"
+	"math/rand"
+	"reflect"
 	"testing"
+	"testing/quick"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -97,6 +100,38 @@ func TestQueryInvalid(t *testing.T) {
 			query:             "shared(foo, bar, com)",
 			expectedErrString: "single argument",
 		},
+		"TopFuncMissingCount": {
+			query:             "top(foo)",
+			expectedErrString: "expected a set and an integer",
+		},
+		"TopFuncWrongTypeSecondArgument": {
+			query:             "top(foo, bar)",
+			expectedErrString: "non-negative integer",
+		},
+		"TopFuncUnknownKeyword": {
+			query:             "top(foo, 3, with=age)",
+			expectedErrString: "unknown keyword",
+		},
+		"TopFuncUnknownMetric": {
+			query:             "top(foo, 3, by=size)",
+			expectedErrString: "unknown metric",
+		},
+		"LeavesFuncTooManyArgs": {
+			query:             "leaves(foo, bar)",
+			expectedErrString: "single argument",
+		},
+		"RootsFuncInteger": {
+			query:             "roots(42)",
+			expectedErrString: "integer",
+		},
+		"DepthFuncMissingDepth": {
+			query:             "depth(foo)",
+			expectedErrString: "expected 2 arguments",
+		},
+		"DepthFuncWrongTypeSecondArgument": {
+			query:             "depth(foo, bar)",
+			expectedErrString: "non-negative integer",
+		},
 		"UnknownFunc": {
 			query:             "foo(bar)",
 			expectedErrString: "unknown function",
@@ -165,6 +200,30 @@ func TestQueryNameMatch(t *testing.T) {
 				"test.com/foo": true,
 			},
 		},
+		"StdLibHidden": {
+			graph: queryTestGraph{
+				nodes: []queryTestNode{
+					{name: "test.com/module"},
+					{name: StdLibModule},
+				},
+			},
+			query: "**",
+			expectedSet: nodeSet{
+				"test.com/module": true,
+			},
+		},
+		"StdLibSelected": {
+			graph: queryTestGraph{
+				nodes: []queryTestNode{
+					{name: "test.com/module"},
+					{name: StdLibModule},
+				},
+			},
+			query: "std",
+			expectedSet: nodeSet{
+				StdLibModule: true,
+			},
+		},
 		"Prefix": {
 			graph: queryTestGraph{
 				nodes: []queryTestNode{
@@ -292,6 +351,24 @@ func TestQueryBinaryOp(t *testing.T) {
 	}
 }
 
+var rankingTestGraph = queryTestGraph{
+	nodes: []queryTestNode{
+		{name: "test.com/module"},
+		{name: "test.com/foo"},
+		{name: "test.com/bar"},
+		{name: "test.com/dead"},
+		{name: "test.com/beef"},
+	},
+	edges: []queryTestEdge{
+		{s: "test.com/module", e: "test.com/foo"},
+		{s: "test.com/module", e: "test.com/bar"},
+		{s: "test.com/module", e: "test.com/dead"},
+		{s: "test.com/foo", e: "test.com/bar"},
+		{s: "test.com/foo", e: "test.com/dead"},
+		{s: "test.com/bar", e: "test.com/beef"},
+	},
+}
+
 func TestQueryFuncs(t *testing.T) {
 	t.Parallel()
 
@@ -402,6 +479,71 @@ func TestQueryFuncs(t *testing.T) {
 				"test.com/bar":    true,
 			},
 		},
+		"TopDefault": {
+			graph:       rankingTestGraph,
+			query:       "top(test.com/**, 2)",
+			expectedSet: nodeSet{"test.com/bar": true, "test.com/dead": true},
+		},
+		"TopByOutDegree": {
+			graph:       rankingTestGraph,
+			query:       "top(test.com/**, 1, by=out_degree)",
+			expectedSet: nodeSet{"test.com/module": true},
+		},
+		"TopByDeps": {
+			graph:       rankingTestGraph,
+			query:       "top(test.com/** - test.com/module, 2, by=deps)",
+			expectedSet: nodeSet{"test.com/foo": true, "test.com/bar": true},
+		},
+		"TopByRDeps": {
+			graph:       rankingTestGraph,
+			query:       "top(test.com/**, 1, by=rdeps)",
+			expectedSet: nodeSet{"test.com/beef": true},
+		},
+		"Leaves": {
+			graph:       rankingTestGraph,
+			query:       "leaves(test.com/**)",
+			expectedSet: nodeSet{"test.com/dead": true, "test.com/beef": true},
+		},
+		"LeavesOfSubset": {
+			graph:       rankingTestGraph,
+			query:       "leaves(test.com/module + test.com/foo + test.com/bar)",
+			expectedSet: nodeSet{"test.com/bar": true},
+		},
+		"Roots": {
+			graph:       rankingTestGraph,
+			query:       "roots(test.com/**)",
+			expectedSet: nodeSet{"test.com/module": true},
+		},
+		"RootsOfSubset": {
+			graph:       rankingTestGraph,
+			query:       "roots(test.com/** - test.com/module)",
+			expectedSet: nodeSet{"test.com/foo": true},
+		},
+		"DepthZero": {
+			graph:       rankingTestGraph,
+			query:       "depth(test.com/module, 0)",
+			expectedSet: nodeSet{"test.com/module": true},
+		},
+		"DepthShortestPath": {
+			graph:       rankingTestGraph,
+			query:       "depth(test.com/module, 2)",
+			expectedSet: nodeSet{"test.com/beef": true},
+		},
+		"DepthMultipleSources": {
+			graph:       rankingTestGraph,
+			query:       "depth(test.com/foo + test.com/bar, 1)",
+			expectedSet: nodeSet{"test.com/dead": true, "test.com/beef": true},
+		},
+		"TopZero": {
+			graph:       rankingTestGraph,
+			query:       "top(test.com/**, 0)",
+			expectedSet: nodeSet{},
+		},
+		"TopMoreThanAvailable": {
+			graph:       rankingTestGraph,
+			query:       "top(test.com/b*, 10)",
+			expectedSet: nodeSet{"test.com/bar": true, "test.com/beef": true},
+		},
 	}
 
 	for name := range testcases {
@@ -422,3 +564,112 @@ func TestQueryFuncs(t *testing.T) {
 		})
 	}
 }
+
+func TestQueryNonDestructive(t *testing.T) {
+	t.Parallel()
+
+	log := testutil.TestLogger(t)
+	g := instantiateQueryTestGraph(t, queryTestGraph{
+		nodes: []queryTestNode{
+			{name: "test.com/module"},
+			{name: "test.com/foo"},
+			{name: "test.com/bar"},
+			{name: "test.com/dead"},
+		},
+		edges: []queryTestEdge{
+			{s: "test.com/module", e: "test.com/foo"},
+			{s: "test.com/foo", e: "test.com/bar"},
+			{s: "test.com/module", e: "test.com/dead"},
+		},
+	})
+
+	q1, err := query.Parse(log, "deps(test.com/foo)")
+	require.NoError(t, err)
+	r1, err := g.Query(log, q1, LevelModules)
+	require.NoError(t, err)
+
+	q2, err := query.Parse(log, "rdeps(test.com/dead)")
+	require.NoError(t, err)
+	r2, err := g.Query(log, q2, LevelModules)
+	require.NoError(t, err)
+
+	nodeNames := func(g *DepGraph) []string {
+		var names []string
+		for _, n := range g.Graph.GetLevel(int(LevelModules)).List() {
+			names = append(names, n.Name())
+		}
+		return names
+	}
+	assert.Equal(t, []string{"test.com/bar", "test.com/foo"}, nodeNames(r1))
+	assert.Equal(t, []string{"test.com/dead", "test.com/module"}, nodeNames(r2))
+	assert.Equal(t, []string{"test.com/bar", "test.com/dead", "test.com/foo", "test.com/module"}, nodeNames(&g))
+
+	foo, err := r1.Graph.GetNode(moduleHash("test.com/foo"))
+	require.NoError(t, err)
+	assert.Equal(t, 1, foo.Successors().Len())
+	assert.Equal(t, 0, foo.Predecessors().Len())
+
+	foo, err = g.Graph.GetNode(moduleHash("test.com/foo"))
+	require.NoError(t, err)
+	assert.Equal(t, 1, foo.Predecessors().Len())
+}
+
+// Generate implements quick.Generator. Sets are drawn from a small universe of names so that
+// randomly generated sets overlap frequently.
+func (nodeSet) Generate(r *rand.Rand, _ int) reflect.Value {
+	set := nodeSet{}
+	for _, name := range []string{"foo", "bar", "dead", "beef", "null", "test"} {
+		if r.Intn(2) == 0 {
+			set[name] = true
+		}
+	}
+	return reflect.ValueOf(set)
+}
+
+func TestNodeSetProperties(t *testing.T) {
+	t.Parallel()
+
+	equal := func(a nodeSet, b nodeSet) bool { return reflect.DeepEqual(a, b) }
+
+	properties := map[string]interface{}{
+		"UnionCommutative": func(a, b nodeSet) bool { return equal(a.union(b), b.union(a)) },
+		"InterCommutative": func(a, b nodeSet) bool { return equal(a.inter(b), b.inter(a)) },
+		"DeltaCommutative": func(a, b nodeSet) bool { return equal(a.delta(b), b.delta(a)) },
+		"UnionAssociative": func(a, b, c nodeSet) bool { return equal(a.union(b).union(c), a.union(b.union(c))) },
+		"InterAssociative": func(a, b, c nodeSet) bool { return equal(a.inter(b).inter(c), a.inter(b.inter(c))) },
+		"DeltaAssociative": func(a, b, c nodeSet) bool { return equal(a.delta(b).delta(c), a.delta(b.delta(c))) },
+		"Identities": func(a nodeSet) bool {
+			empty := nodeSet{}
+			return equal(a.union(empty), a) &&
+				equal(a.inter(empty), empty) &&
+				equal(a.delta(empty), a) &&
+				equal(a.subtract(empty), a) &&
+				equal(a.union(a), a) &&
+				equal(a.inter(a), a) &&
+				equal(a.delta(a), empty) &&
+				equal(a.subtract(a), empty)
+		},
+		"DeltaDefinition": func(a, b nodeSet) bool {
+			return equal(a.delta(b), a.subtract(b).union(b.subtract(a)))
+		},
+		"SubtractDeMorgan": func(a, b, c nodeSet) bool {
+			return equal(a.subtract(b.union(c)), a.subtract(b).inter(a.subtract(c)))
+		},
+		"InterDistributive": func(a, b, c nodeSet) bool {
+			return equal(a.inter(b.union(c)), a.inter(b).union(a.inter(c)))
+		},
+		"InputsUnmodified": func(a, b nodeSet) bool {
+			origA, origB := a.union(nodeSet{}), b.union(nodeSet{})
+			_, _, _, _ = a.union(b), a.inter(b), a.delta(b), a.subtract(b)
+			return equal(a, origA) && equal(b, origB)
+		},
+	}
+
+	for name := range properties {
+		property := properties[name]
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			assert.NoError(t, quick.Check(property, nil))
+		})
+	}
+}
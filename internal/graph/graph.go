@@ -100,11 +100,15 @@ func (g *HierarchicalDigraph) DeleteNode(hash string) error {
 	}
 
 	for _, pred := range target.Predecessors().List() {
+		_, w := pred.Successors().Get(target.Hash())
 		g.disconnectNodeFromTarget(pred, target)
+		g.unregisterEdgeFromParents(pred, target, w)
 	}
 
 	for _, succ := range target.Successors().List() {
+		_, w := target.Successors().Get(succ.Hash())
 		g.disconnectNodeFromTarget(target, succ)
+		g.unregisterEdgeFromParents(target, succ, w)
 	}
 
 	g.deleteNode(target)
@@ -188,9 +192,16 @@ func (g *HierarchicalDigraph) DeleteEdge(src Node, dst Node) error {
 		}
 	}
 
+	_, w := src.Successors().Get(dst.Hash())
 	g.disconnectNodeFromTarget(src, dst)
+	g.unregisterEdgeFromParents(src, dst, w)
+	return nil
+}
 
-	for {
+// unregisterEdgeFromParents removes the contribution of an edge of the given weight between two
+// nodes from the edges between their respective ancestors.
+func (g HierarchicalDigraph) unregisterEdgeFromParents(src Node, dst Node, weight int) {
+	for weight > 0 {
 		g.log.AddIndent()
 		defer g.log.RemoveIndent()
 
@@ -201,10 +212,11 @@ func (g *HierarchicalDigraph) DeleteEdge(src Node, dst Node) error {
 		}
 
 		g.log.Debug("Unregistring edge from node parents.", zap.String("source-hash", src.Hash()), zap.String("target-hash", dst.Hash()))
-		src.Successors().Delete(dst.Hash())
-		dst.Predecessors().Delete(src.Hash())
+		for i := 0; i < weight; i++ {
+			src.Successors().Delete(dst.Hash())
+			dst.Predecessors().Delete(src.Hash())
+		}
 	}
-	return nil
 }
 
 func (g HierarchicalDigraph) GetLevel(level int) NodeRefs {
@@ -217,6 +229,102 @@ func (g HierarchicalDigraph) GetLevel(level int) NodeRefs {
 	return refs
 }
 
+// Clone returns a deep copy of the graph. Nodes are duplicated via their Copy method and all edges,
+// including their weights, are reproduced between the copies. Modifications to the returned graph
+// do not affect the original one.
+func (g *HierarchicalDigraph) Clone() (*HierarchicalDigraph, error) {
+	if g == nil {
+		return nil, ErrNilGraph
+	}
+	g.log.Debug("Cloning graph.")
+
+	clone := NewHierarchicalDigraph(g.log)
+	copies := map[string]Node{}
+	for level := 0; ; level++ {
+		nodes := g.GetLevel(level)
+		if nodes.Len() == 0 {
+			break
+		}
+		for _, n := range nodes.List() {
+			var parent Node
+			if p := n.Parent(); !nodeIsNil(p) {
+				parent = copies[p.Hash()]
+			}
+			c := n.Copy(parent)
+			if err := clone.AddNode(c); err != nil {
+				return nil, err
+			}
+			copies[n.Hash()] = c
+		}
+	}
+
+	for _, n := range g.members.nodeList {
+		c := copies[n.Hash()]
+		for _, succ := range n.Successors().nodeList {
+			_, w := n.Successors().Get(succ.Hash())
+			c.Successors().addWeighted(copies[succ.Hash()], w)
+		}
+		for _, pred := range n.Predecessors().nodeList {
+			_, w := n.Predecessors().Get(pred.Hash())
+			c.Predecessors().addWeighted(copies[pred.Hash()], w)
+		}
+	}
+	return clone, nil
+}
+
+// Subgraph returns a new graph induced by the nodes with the specified hashes. Next to the selected
+// nodes themselves the result retains all of their ancestors and descendants so that the hierarchy
+// remains intact. Edges are only kept when both their ends are part of the result. The original
+// graph is left untouched.
+func (g *HierarchicalDigraph) Subgraph(hashes []string) (*HierarchicalDigraph, error) {
+	if g == nil {
+		return nil, ErrNilGraph
+	}
+
+	keep := map[string]bool{}
+	for _, hash := range hashes {
+		n, _ := g.members.Get(hash)
+		if n == nil {
+			return nil, &graphErr{
+				err: ErrNodeNotFound,
+				ctx: fmt.Sprintf("node hash %q", hash),
+			}
+		}
+		for p := n; !nodeIsNil(p); p = p.Parent() {
+			keep[p.Hash()] = true
+		}
+		markDescendants(n, keep)
+	}
+
+	sub, err := g.Clone()
+	if err != nil {
+		return nil, err
+	}
+	g.log.Debug("Pruning cloned graph to subgraph.", zap.Int("selected", len(hashes)))
+	for _, n := range sub.members.List() {
+		if keep[n.Hash()] {
+			continue
+		}
+		if m, _ := sub.members.Get(n.Hash()); m == nil {
+			continue // Already removed together with an ancestor.
+		}
+		if err = sub.DeleteNode(n.Hash()); err != nil {
+			return nil, err
+		}
+	}
+	return sub, nil
+}
+
+func markDescendants(n Node, marks map[string]bool) {
+	if n.Children() == nil {
+		return
+	}
+	for _, child := range n.Children().List() {
+		marks[child.Hash()] = true
+		markDescendants(child, marks)
+	}
+}
+
 func (g HierarchicalDigraph) disconnectNodeFromTarget(n Node, target Node) {
 	g.log.Debug("Disconnecting nodes.", zap.String("source-hash", n.Hash()), zap.String("target-hash", target.Hash()))
 	g.log.AddIndent()
@@ -238,7 +346,7 @@ func (g HierarchicalDigraph) disconnectNodeFromTarget(n Node, target Node) {
 	}
 }
 
-func (g HierarchicalDigraph) deleteNode(n Node) {
+func (g *HierarchicalDigraph) deleteNode(n Node) {
 	if n.Children() != nil && n.Children().Len() > 0 {
 		g.log.AddIndent()
 		for _, child := range n.Children().List() {
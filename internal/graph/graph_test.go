@@ -187,3 +187,123 @@ func TestGraphEdges(t *testing.T) {
 		assert.True(t, errors.Is(err, ErrNodeNotFound))
 	})
 }
+
+func TestGraphDeleteParentEdgeWeights(t *testing.T) {
+	g := NewHierarchicalDigraph(testutil.TestLogger(t).Log())
+
+	n1 := newTestNode("test-node-1", nil)
+	n2 := newTestNode("test-node-2", nil)
+	nc1 := newTestNode("test-node-child-1", n1)
+	nc2 := newTestNode("test-node-child-2", n2)
+	nc3 := newTestNode("test-node-child-3", n2)
+
+	for _, n := range []*testNode{n1, n2, nc1, nc2, nc3} {
+		require.NoError(t, g.AddNode(n))
+	}
+	require.NoError(t, g.AddEdge(nc1, nc2))
+	require.NoError(t, g.AddEdge(nc1, nc3))
+
+	_, w := n1.Successors().Get(n2.name)
+	require.Equal(t, 2, w)
+
+	require.NoError(t, g.DeleteNode(nc3.name))
+	assert.Equal(t, 2, g.GetLevel(1).Len())
+	_, w = n1.Successors().Get(n2.name)
+	assert.Equal(t, 1, w)
+	_, w = n2.Predecessors().Get(n1.name)
+	assert.Equal(t, 1, w)
+
+	require.NoError(t, g.DeleteEdge(nc1, nc2))
+	assert.Equal(t, 0, n1.Successors().Len())
+	assert.Equal(t, 0, n2.Predecessors().Len())
+}
+
+func TestGraphClone(t *testing.T) {
+	g := NewHierarchicalDigraph(testutil.TestLogger(t).Log())
+
+	n1 := newTestNode("test-node-1", nil)
+	n2 := newTestNode("test-node-2", nil)
+	nc1 := newTestNode("test-node-child-1", n1)
+	nc2 := newTestNode("test-node-child-2", n2)
+
+	require.NoError(t, g.AddNode(n1))
+	require.NoError(t, g.AddNode(n2))
+	require.NoError(t, g.AddNode(nc1))
+	require.NoError(t, g.AddNode(nc2))
+	require.NoError(t, g.AddEdge(nc1, nc2))
+	require.NoError(t, g.AddEdge(n1, n2))
+
+	c, err := g.Clone()
+	require.NoError(t, err)
+
+	cn1, err := c.GetNode(n1.name)
+	require.NoError(t, err)
+	assert.NotSame(t, n1, cn1)
+	cnc1, err := c.GetNode(nc1.name)
+	require.NoError(t, err)
+	assert.Equal(t, cn1, cnc1.Parent())
+	assert.Equal(t, 1, cn1.Children().Len())
+
+	m, w := cn1.Successors().Get(n2.name)
+	require.NotNil(t, m)
+	assert.NotSame(t, n2, m)
+	assert.Equal(t, 2, w)
+	_, w = cnc1.Successors().Get(nc2.name)
+	assert.Equal(t, 1, w)
+
+	require.NoError(t, c.DeleteNode(n2.name))
+	_, w = n1.Successors().Get(n2.name)
+	assert.Equal(t, 2, w)
+	_, err = g.GetNode(nc2.name)
+	assert.NoError(t, err)
+}
+
+func TestGraphSubgraph(t *testing.T) {
+	g := NewHierarchicalDigraph(testutil.TestLogger(t).Log())
+
+	n1 := newTestNode("test-node-1", nil)
+	n2 := newTestNode("test-node-2", nil)
+	n3 := newTestNode("test-node-3", nil)
+	nc1 := newTestNode("test-node-child-1", n1)
+	nc2 := newTestNode("test-node-child-2", n2)
+	nc3 := newTestNode("test-node-child-3", n2)
+
+	for _, n := range []*testNode{n1, n2, n3, nc1, nc2, nc3} {
+		require.NoError(t, g.AddNode(n))
+	}
+	require.NoError(t, g.AddEdge(nc1, nc2))
+	require.NoError(t, g.AddEdge(nc1, nc3))
+	require.NoError(t, g.AddEdge(n2, n3))
+
+	t.Run("UnknownNode", func(t *testing.T) {
+		_, err := g.Subgraph([]string{"non-member"})
+		assert.True(t, errors.Is(err, ErrNodeNotFound))
+	})
+
+	t.Run("TopLevel", func(t *testing.T) {
+		s, err := g.Subgraph([]string{n1.name, n2.name})
+		require.NoError(t, err)
+		assert.Equal(t, 2, s.GetLevel(0).Len())
+		assert.Equal(t, 3, s.GetLevel(1).Len())
+
+		sn2, err := s.GetNode(n2.name)
+		require.NoError(t, err)
+		assert.Equal(t, 0, sn2.Successors().Len())
+		assert.Equal(t, 1, sn2.Predecessors().Len())
+	})
+
+	t.Run("LowerLevel", func(t *testing.T) {
+		s, err := g.Subgraph([]string{nc1.name, nc2.name})
+		require.NoError(t, err)
+		assert.Equal(t, 2, s.GetLevel(0).Len())
+		assert.Equal(t, 2, s.GetLevel(1).Len())
+
+		sn1, err := s.GetNode(n1.name)
+		require.NoError(t, err)
+		_, w := sn1.Successors().Get(n2.name)
+		assert.Equal(t, 1, w)
+	})
+
+	assert.Equal(t, 3, g.GetLevel(0).Len())
+	assert.Equal(t, 3, g.GetLevel(1).Len())
+}
@@ -16,6 +16,11 @@ type Node interface {
 
 	Parent() Node
 	Children() *NodeRefs
+
+	// Copy returns a new node carrying the same payload as the receiver but without any edges or
+	// children. The copy is attached to the specified parent which is expected to be a copy of the
+	// receiver's own parent.
+	Copy(parent Node) Node
 }
 
 func nodeIsNil(n Node) bool {
@@ -70,6 +75,15 @@ func (n *NodeRefs) Add(node Node) {
 	}
 }
 
+func (n *NodeRefs) addWeighted(node Node, weight int) {
+	h := node.Hash()
+	n.weights[h] += weight
+	if _, ok := n.nodeMap[h]; !ok {
+		n.nodeMap[h] = node
+		n.nodeList = append(n.nodeList, node)
+	}
+}
+
 func (n NodeRefs) Get(hash string) (Node, int) {
 	return n.nodeMap[hash], n.weights[hash]
 }
@@ -25,6 +25,10 @@ func (n *testNode) Predecessors() *NodeRefs { return &n.preds }
 func (n *testNode) Successors() *NodeRefs   { return &n.succs }
 func (n *testNode) Parent() Node            { return n.parent }
 func (n *testNode) Children() *NodeRefs     { return &n.children }
+func (n *testNode) Copy(parent Node) Node {
+	p, _ := parent.(*testNode)
+	return newTestNode(n.name, p)
+}
 
 func newTestNode(name string, parent *testNode) *testNode {
 	return &testNode{
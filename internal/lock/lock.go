@@ -0,0 +1,176 @@
+package lock
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v3"
+
+	"github.com/Helcaraxan/gomod/internal/depgraph"
+	"github.com/Helcaraxan/gomod/internal/logger"
+)
+
+const (
+	// DefaultPath is the location of the lock file, relative to the working directory, when none is
+	// given.
+	DefaultPath = "gomod.lock"
+
+	edgeSeparator = " -> "
+	header        = `# Approved modules and module-to-module dependencies of this module, as recorded by 'gomod lock'.
+# Use 'gomod lock --verify' to detect new modules or dependencies that are not recorded here.
+`
+)
+
+// Snapshot is the set of modules of a dependency graph and of the dependencies between them.
+// Versions are not recorded so that upgrades of already approved modules do not cause any drift.
+type Snapshot struct {
+	Modules []string `yaml:"modules"`
+	Edges   []string `yaml:"edges"`
+}
+
+// New records the modules, including test-only ones, and the dependencies between them of the
+// given graph. Both lists are sorted and edges are written as 'source -> target'.
+func New(g *depgraph.DepGraph) *Snapshot {
+	s := &Snapshot{Modules: []string{}, Edges: []string{}}
+	for _, node := range g.Graph.GetLevel(int(depgraph.LevelModules)).List() {
+		if node.Name() == depgraph.StdLibModule {
+			continue
+		}
+		s.Modules = append(s.Modules, node.Name())
+		for _, dep := range node.Successors().List() {
+			if dep.Name() == depgraph.StdLibModule {
+				continue
+			}
+			s.Edges = append(s.Edges, node.Name()+edgeSeparator+dep.Name())
+		}
+	}
+	sort.Strings(s.Modules)
+	sort.Strings(s.Edges)
+	return s
+}
+
+// Read loads a snapshot from the lock file at the given path.
+func Read(log *logger.Logger, path string) (*Snapshot, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		log.Error("Could not read lock file. Run 'gomod lock' to create it.", zap.String("path", path), zap.Error(err))
+		return nil, err
+	}
+
+	s := &Snapshot{}
+	if err = yaml.Unmarshal(content, s); err != nil {
+		log.Error("Could not parse lock file.", zap.String("path", path), zap.Error(err))
+		return nil, errors.New("invalid lock file")
+	}
+	for _, edge := range s.Edges {
+		if len(strings.Split(edge, edgeSeparator)) != 2 {
+			log.Error("Could not parse edge of lock file. Expected 'source -> target'.", zap.String("path", path), zap.String("edge", edge))
+			return nil, errors.New("invalid lock file")
+		}
+	}
+	return s, nil
+}
+
+// Write stores the snapshot in the lock file at the given path.
+func (s *Snapshot) Write(log *logger.Logger, path string) error {
+	content := bytes.NewBufferString(header)
+	encoder := yaml.NewEncoder(content)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(s); err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(path, content.Bytes(), 0o644); err != nil {
+		log.Error("Could not write lock file.", zap.String("path", path), zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// Diff lists the modules and dependencies that are only part of one of two snapshots.
+type Diff struct {
+	AddedModules   []string
+	RemovedModules []string
+	AddedEdges     []string
+	RemovedEdges   []string
+}
+
+// Compare returns what changed between the locked snapshot and the current one.
+func Compare(locked *Snapshot, current *Snapshot) *Diff {
+	d := &Diff{}
+	d.AddedModules, d.RemovedModules = difference(locked.Modules, current.Modules)
+	d.AddedEdges, d.RemovedEdges = difference(locked.Edges, current.Edges)
+	return d
+}
+
+// HasAdditions indicates whether the current snapshot contains modules or dependencies that have not
+// been locked. These are the changes that require the lock file to be updated.
+func (d *Diff) HasAdditions() bool {
+	return len(d.AddedModules) > 0 || len(d.AddedEdges) > 0
+}
+
+// HasRemovals indicates whether locked modules or dependencies are no longer part of the graph.
+func (d *Diff) HasRemovals() bool {
+	return len(d.RemovedModules) > 0 || len(d.RemovedEdges) > 0
+}
+
+// Print writes the differences in a format akin to that of a unified diff, with added lines prefixed
+// by '+' and removed ones by '-'.
+func (d *Diff) Print(w io.Writer) error {
+	if !d.HasAdditions() && !d.HasRemovals() {
+		_, err := fmt.Fprintln(w, "The dependency graph matches the lock file.")
+		return err
+	}
+
+	var lines []string
+	for _, section := range []struct {
+		title   string
+		added   []string
+		removed []string
+	}{
+		{title: "modules", added: d.AddedModules, removed: d.RemovedModules},
+		{title: "edges", added: d.AddedEdges, removed: d.RemovedEdges},
+	} {
+		if len(section.added) == 0 && len(section.removed) == 0 {
+			continue
+		}
+		lines = append(lines, section.title+":")
+		for _, item := range section.added {
+			lines = append(lines, "+ "+item)
+		}
+		for _, item := range section.removed {
+			lines = append(lines, "- "+item)
+		}
+	}
+	_, err := fmt.Fprintln(w, strings.Join(lines, "\n"))
+	return err
+}
+
+// difference returns the sorted items that are only part of the current list and those that are only
+// part of the locked one.
+func difference(locked []string, current []string) (added []string, removed []string) {
+	inLocked := make(map[string]bool, len(locked))
+	for _, item := range locked {
+		inLocked[item] = true
+	}
+	inCurrent := make(map[string]bool, len(current))
+	for _, item := range current {
+		inCurrent[item] = true
+		if !inLocked[item] {
+			added = append(added, item)
+		}
+	}
+	for _, item := range locked {
+		if !inCurrent[item] {
+			removed = append(removed, item)
+		}
+	}
+	sort.Strings(added)
+	sort.Strings(removed)
+	return added, removed
+}
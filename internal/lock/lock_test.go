@@ -0,0 +1,150 @@
+package lock
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Helcaraxan/gomod/internal/depgraph"
+	"github.com/Helcaraxan/gomod/internal/testutil"
+)
+
+func TestSnapshot(t *testing.T) {
+	cwd, err := os.Getwd()
+	require.NoError(t, err)
+
+	testDir := testutil.SetupTestModule(t, filepath.Join(cwd, "testdata", "Lock.yaml"), &testutil.GraphTestDefinition{})
+	log := testutil.TestLogger(t)
+	g, err := depgraph.GetGraph(log, testDir, nil)
+	require.NoError(t, err)
+
+	snapshot := New(g)
+	assert.Equal(t, &Snapshot{
+		Modules: []string{"example.com/a", "example.com/b", "example.com/c", "test"},
+		Edges: []string{
+			"example.com/a -> example.com/b",
+			"example.com/c -> example.com/a",
+			"test -> example.com/a",
+			"test -> example.com/b",
+			"test -> example.com/c",
+		},
+	}, snapshot)
+
+	path := filepath.Join(t.TempDir(), DefaultPath)
+	require.NoError(t, snapshot.Write(log.Log(), path))
+	content, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, header+`modules:
+  - example.com/a
+  - example.com/b
+  - example.com/c
+  - test
+edges:
+  - example.com/a -> example.com/b
+  - example.com/c -> example.com/a
+  - test -> example.com/a
+  - test -> example.com/b
+  - test -> example.com/c
+`, string(content))
+
+	read, err := Read(log.Log(), path)
+	require.NoError(t, err)
+	assert.Equal(t, snapshot, read)
+}
+
+func TestRead(t *testing.T) {
+	testcases := map[string]struct {
+		content       string
+		expectedError bool
+	}{
+		"Valid": {
+			content: "modules:\n  - a\n  - b\nedges:\n  - a -> b\n",
+		},
+		"InvalidEdge": {
+			content:       "modules:\n  - a\nedges:\n  - a\n",
+			expectedError: true,
+		},
+		"InvalidYAML": {
+			content:       "modules: [a\n",
+			expectedError: true,
+		},
+	}
+
+	for name := range testcases {
+		testcase := testcases[name]
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), DefaultPath)
+			require.NoError(t, ioutil.WriteFile(path, []byte(testcase.content), 0o600))
+
+			_, err := Read(testutil.TestLogger(t).Log(), path)
+			if testcase.expectedError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+
+	_, err := Read(testutil.TestLogger(t).Log(), filepath.Join(t.TempDir(), "missing.lock"))
+	assert.Error(t, err)
+}
+
+func TestCompare(t *testing.T) {
+	locked := &Snapshot{
+		Modules: []string{"a", "b", "old"},
+		Edges:   []string{"a -> b", "a -> old"},
+	}
+
+	t.Run("Identical", func(t *testing.T) {
+		d := Compare(locked, locked)
+		assert.False(t, d.HasAdditions())
+		assert.False(t, d.HasRemovals())
+
+		var out bytes.Buffer
+		require.NoError(t, d.Print(&out))
+		assert.Equal(t, "The dependency graph matches the lock file.\n", out.String())
+	})
+
+	t.Run("Drift", func(t *testing.T) {
+		d := Compare(locked, &Snapshot{
+			Modules: []string{"a", "b", "new"},
+			Edges:   []string{"a -> b", "b -> new"},
+		})
+		assert.Equal(t, &Diff{
+			AddedModules:   []string{"new"},
+			RemovedModules: []string{"old"},
+			AddedEdges:     []string{"b -> new"},
+			RemovedEdges:   []string{"a -> old"},
+		}, d)
+		assert.True(t, d.HasAdditions())
+		assert.True(t, d.HasRemovals())
+
+		var out bytes.Buffer
+		require.NoError(t, d.Print(&out))
+		assert.Equal(t, `modules:
++ new
+- old
+edges:
++ b -> new
+- a -> old
+`, out.String())
+	})
+
+	t.Run("NewEdgeOnly", func(t *testing.T) {
+		d := Compare(locked, &Snapshot{
+			Modules: []string{"a", "b", "old"},
+			Edges:   []string{"a -> b", "a -> old", "b -> old"},
+		})
+		assert.True(t, d.HasAdditions())
+		assert.False(t, d.HasRemovals())
+
+		var out bytes.Buffer
+		require.NoError(t, d.Print(&out))
+		assert.Equal(t, "edges:\n+ b -> old\n", out.String())
+	})
+}
@@ -114,3 +114,27 @@ func (l *Logger) RemoveIndent() {
 		l.enc.indent--
 	}
 }
+
+// DemoteWarnings returns a logger that emits warnings at debug level instead. It is meant for callers
+// that reuse code of which the warnings are expected, and thus not relevant to users, in their context.
+func (l *Logger) DemoteWarnings() *Logger {
+	return &Logger{
+		Logger: l.WithOptions(zap.WrapCore(func(core zapcore.Core) zapcore.Core { return demotingCore{core} })),
+		enc:    l.enc,
+	}
+}
+
+type demotingCore struct {
+	zapcore.Core
+}
+
+func (c demotingCore) With(fields []zapcore.Field) zapcore.Core {
+	return demotingCore{c.Core.With(fields)}
+}
+
+func (c demotingCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if entry.Level == zapcore.WarnLevel {
+		entry.Level = zapcore.DebugLevel
+	}
+	return c.Core.Check(entry, checked)
+}
@@ -0,0 +1,45 @@
+package parsers
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/Helcaraxan/gomod/internal/depgraph"
+	"github.com/Helcaraxan/gomod/internal/logger"
+)
+
+var edgeFilterRE = regexp.MustCompile(`^weight\s*(>=|<=|>|<|=)\s*([0-9]+)$`)
+
+func ParseEdgeFilter(log *logger.Logger, raw string) (*depgraph.EdgeFilter, error) {
+	m := edgeFilterRE.FindStringSubmatch(strings.ToLower(strings.TrimSpace(raw)))
+	if m == nil {
+		log.Error("Could not parse edge filter. Expected 'weight' followed by one of '>', '>=', '<', '<=' or '=' and a number.", zap.String("value", raw))
+		return nil, errors.New("invalid edge filter")
+	}
+
+	filter := &depgraph.EdgeFilter{}
+	switch m[1] {
+	case ">":
+		filter.Comparison = depgraph.Greater
+	case ">=":
+		filter.Comparison = depgraph.GreaterOrEqual
+	case "<":
+		filter.Comparison = depgraph.Less
+	case "<=":
+		filter.Comparison = depgraph.LessOrEqual
+	default:
+		filter.Comparison = depgraph.Equal
+	}
+
+	weight, err := strconv.Atoi(m[2])
+	if err != nil {
+		log.Error("Could not parse the weight of the edge filter.", zap.String("value", raw), zap.Error(err))
+		return nil, errors.New("invalid edge filter")
+	}
+	filter.Weight = weight
+	return filter, nil
+}
@@ -0,0 +1,70 @@
+package parsers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Helcaraxan/gomod/internal/depgraph"
+	"github.com/Helcaraxan/gomod/internal/testutil"
+)
+
+func TestEdgeFilter(t *testing.T) {
+	testcases := map[string]struct {
+		value          string
+		expectedFilter *depgraph.EdgeFilter
+		expectedError  bool
+	}{
+		"Greater": {
+			value:          "weight>5",
+			expectedFilter: &depgraph.EdgeFilter{Comparison: depgraph.Greater, Weight: 5},
+		},
+		"GreaterOrEqual": {
+			value:          "weight >= 2",
+			expectedFilter: &depgraph.EdgeFilter{Comparison: depgraph.GreaterOrEqual, Weight: 2},
+		},
+		"Less": {
+			value:          "Weight<10",
+			expectedFilter: &depgraph.EdgeFilter{Comparison: depgraph.Less, Weight: 10},
+		},
+		"LessOrEqual": {
+			value:          "weight<=0",
+			expectedFilter: &depgraph.EdgeFilter{Comparison: depgraph.LessOrEqual, Weight: 0},
+		},
+		"Equal": {
+			value:          "weight=1",
+			expectedFilter: &depgraph.EdgeFilter{Comparison: depgraph.Equal, Weight: 1},
+		},
+		"UnknownProperty": {
+			value:         "count>5",
+			expectedError: true,
+		},
+		"MissingWeight": {
+			value:         "weight>",
+			expectedError: true,
+		},
+		"NegativeWeight": {
+			value:         "weight>-1",
+			expectedError: true,
+		},
+		"UnknownComparison": {
+			value:         "weight!=3",
+			expectedError: true,
+		},
+	}
+
+	for name := range testcases {
+		testcase := testcases[name]
+		t.Run(name, func(t *testing.T) {
+			log := testutil.TestLogger(t)
+			filter, err := ParseEdgeFilter(log.Log(), testcase.value)
+			if testcase.expectedError {
+				assert.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, testcase.expectedFilter, filter)
+			}
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package parsers
+
+import (
+	"errors"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/Helcaraxan/gomod/internal/logger"
+	"github.com/Helcaraxan/gomod/internal/printer"
+)
+
+func ParseFormat(log *logger.Logger, raw string) (printer.Format, error) {
+	switch strings.ToLower(strings.TrimSpace(raw)) {
+	case "", "dot":
+		return printer.FormatDOT, nil
+	case "mermaid":
+		return printer.FormatMermaid, nil
+	case "graphml":
+		return printer.FormatGraphML, nil
+	case "gexf":
+		return printer.FormatGEXF, nil
+	case "json":
+		return printer.FormatJSON, nil
+	case "html":
+		return printer.FormatHTML, nil
+	case "csv":
+		return printer.FormatCSV, nil
+	case "tsv":
+		return printer.FormatTSV, nil
+	default:
+		log.Error("Unknown output format. Accepted values are 'dot', 'mermaid', 'graphml', 'gexf', 'json', 'html', 'csv' and 'tsv'.", zap.String("value", raw))
+		return 0, errors.New("invalid output format")
+	}
+}
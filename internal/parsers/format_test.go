@@ -0,0 +1,74 @@
+package parsers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Helcaraxan/gomod/internal/printer"
+	"github.com/Helcaraxan/gomod/internal/testutil"
+)
+
+func TestFormat(t *testing.T) {
+	testcases := map[string]struct {
+		value          string
+		expectedFormat printer.Format
+		expectedError  bool
+	}{
+		"Empty": {
+			value:          "",
+			expectedFormat: printer.FormatDOT,
+		},
+		"DOT": {
+			value:          "dot",
+			expectedFormat: printer.FormatDOT,
+		},
+		"Mermaid": {
+			value:          "Mermaid",
+			expectedFormat: printer.FormatMermaid,
+		},
+		"GraphML": {
+			value:          "graphml",
+			expectedFormat: printer.FormatGraphML,
+		},
+		"GEXF": {
+			value:          "GEXF",
+			expectedFormat: printer.FormatGEXF,
+		},
+		"JSON": {
+			value:          "json",
+			expectedFormat: printer.FormatJSON,
+		},
+		"HTML": {
+			value:          "html",
+			expectedFormat: printer.FormatHTML,
+		},
+		"CSV": {
+			value:          "csv",
+			expectedFormat: printer.FormatCSV,
+		},
+		"TSV": {
+			value:          "TSV",
+			expectedFormat: printer.FormatTSV,
+		},
+		"Unknown": {
+			value:         "svg",
+			expectedError: true,
+		},
+	}
+
+	for name := range testcases {
+		testcase := testcases[name]
+		t.Run(name, func(t *testing.T) {
+			log := testutil.TestLogger(t)
+			format, err := ParseFormat(log.Log(), testcase.value)
+			if testcase.expectedError {
+				assert.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, testcase.expectedFormat, format)
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package parsers
+
+import (
+	"errors"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v3"
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v3"
+
+	"github.com/Helcaraxan/gomod/internal/depgraph"
+	"github.com/Helcaraxan/gomod/internal/logger"
+	"github.com/Helcaraxan/gomod/internal/policy"
+)
+
+var versionConstraintRE = regexp.MustCompile(`^(>=|<=|>|<|=)?\s*(\S+)$`)
+
+// policyFile is the content of a policy file as written by users.
+type policyFile struct {
+	Banned  []policyFileEntry `yaml:"banned"`
+	Allowed []policyFileEntry `yaml:"allowed"`
+}
+
+type policyFileEntry struct {
+	Module   string `yaml:"module"`
+	Versions string `yaml:"versions"`
+	Reason   string `yaml:"reason"`
+}
+
+// ParsePolicy reads the lists of banned and allowed modules from a YAML file. Each entry has a glob
+// pattern matched against module paths, an optional range of versions such as '>=v1.2.0, <v2.0.0' and an
+// optional reason.
+func ParsePolicy(dl *logger.Builder, path string) (*policy.Policy, error) {
+	log := dl.Domain(logger.InitDomain)
+
+	f, err := os.Open(path)
+	if err != nil {
+		log.Error("Could not open policy file.", zap.String("path", path), zap.Error(err))
+		return nil, err
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	var raw policyFile
+	decoder := yaml.NewDecoder(f)
+	decoder.KnownFields(true)
+	if err = decoder.Decode(&raw); err != nil {
+		log.Error("Could not parse policy file.", zap.String("path", path), zap.Error(err))
+		return nil, errors.New("invalid policy file")
+	}
+
+	p := &policy.Policy{}
+	for _, list := range []struct {
+		name   string
+		raw    []policyFileEntry
+		target *[]policy.Entry
+	}{
+		{name: "banned", raw: raw.Banned, target: &p.Banned},
+		{name: "allowed", raw: raw.Allowed, target: &p.Allowed},
+	} {
+		for idx, r := range list.raw {
+			entry, err := parsePolicyEntry(log, r)
+			if err != nil {
+				log.Error("Invalid policy entry.", zap.String("list", list.name), zap.Int("entry", idx+1))
+				return nil, err
+			}
+			*list.target = append(*list.target, entry)
+		}
+	}
+	return p, nil
+}
+
+func parsePolicyEntry(log *logger.Logger, raw policyFileEntry) (policy.Entry, error) {
+	entry := policy.Entry{Pattern: raw.Module, Reason: raw.Reason}
+	if entry.Pattern == "" {
+		log.Error("Policy entry does not have a 'module'.")
+		return entry, errors.New("invalid policy entry")
+	}
+	if _, err := doublestar.Match(entry.Pattern, entry.Pattern); err != nil {
+		log.Error("Could not parse 'module' pattern of policy entry.", zap.String("value", raw.Module), zap.Error(err))
+		return entry, errors.New("invalid policy entry")
+	}
+
+	if strings.TrimSpace(raw.Versions) == "" {
+		return entry, nil
+	}
+	for _, constraint := range strings.Split(raw.Versions, ",") {
+		m := versionConstraintRE.FindStringSubmatch(strings.TrimSpace(constraint))
+		if m == nil || !policy.IsValidVersion(m[2]) {
+			log.Error("Could not parse 'versions' of policy entry. Expected a comma-separated list of versions such as 'v1.2.3' optionally preceded by one of '>', '>=', '<', '<=' or '='.", zap.String("value", raw.Versions))
+			return entry, errors.New("invalid policy entry")
+		}
+
+		c := policy.VersionConstraint{Version: m[2]}
+		switch m[1] {
+		case ">":
+			c.Comparison = depgraph.Greater
+		case ">=":
+			c.Comparison = depgraph.GreaterOrEqual
+		case "<":
+			c.Comparison = depgraph.Less
+		case "<=":
+			c.Comparison = depgraph.LessOrEqual
+		default:
+			c.Comparison = depgraph.Equal
+		}
+		entry.Versions = append(entry.Versions, c)
+	}
+	return entry, nil
+}
+
+func ParsePolicyFormat(log *logger.Logger, raw string) (policy.Format, error) {
+	switch strings.ToLower(strings.TrimSpace(raw)) {
+	case "", "text":
+		return policy.FormatText, nil
+	case "json":
+		return policy.FormatJSON, nil
+	case "sarif":
+		return policy.FormatSARIF, nil
+	default:
+		log.Error("Unknown output format. Accepted values are 'text', 'json' and 'sarif'.", zap.String("value", raw))
+		return 0, errors.New("invalid output format")
+	}
+}
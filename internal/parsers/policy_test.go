@@ -0,0 +1,110 @@
+package parsers
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Helcaraxan/gomod/internal/depgraph"
+	"github.com/Helcaraxan/gomod/internal/policy"
+	"github.com/Helcaraxan/gomod/internal/testutil"
+)
+
+func TestPolicy(t *testing.T) {
+	testcases := map[string]struct {
+		content        string
+		expectedPolicy *policy.Policy
+		expectedError  bool
+	}{
+		"Empty": {
+			content:        "{}\n",
+			expectedPolicy: &policy.Policy{},
+		},
+		"Lists": {
+			content: `banned:
+  - module: github.com/pkg/errors
+    reason: use the standard library
+  - module: github.com/gogo/protobuf
+    versions: <v1.3.2
+allowed:
+  - module: go.uber.org/**
+  - module: github.com/spf13/cobra
+    versions: ">= v1.0.0, <v2.0.0"
+`,
+			expectedPolicy: &policy.Policy{
+				Banned: []policy.Entry{
+					{Pattern: "github.com/pkg/errors", Reason: "use the standard library"},
+					{Pattern: "github.com/gogo/protobuf", Versions: policy.VersionRange{{Comparison: depgraph.Less, Version: "v1.3.2"}}},
+				},
+				Allowed: []policy.Entry{
+					{Pattern: "go.uber.org/**"},
+					{Pattern: "github.com/spf13/cobra", Versions: policy.VersionRange{
+						{Comparison: depgraph.GreaterOrEqual, Version: "v1.0.0"},
+						{Comparison: depgraph.Less, Version: "v2.0.0"},
+					}},
+				},
+			},
+		},
+		"ExactVersion": {
+			content: `banned:
+  - module: example.com/a
+    versions: v1.0.0-rc.1
+`,
+			expectedPolicy: &policy.Policy{
+				Banned: []policy.Entry{{Pattern: "example.com/a", Versions: policy.VersionRange{{Comparison: depgraph.Equal, Version: "v1.0.0-rc.1"}}}},
+			},
+		},
+		"MissingModule": {
+			content: `banned:
+  - reason: nothing to ban
+`,
+			expectedError: true,
+		},
+		"InvalidPattern": {
+			content: `allowed:
+  - module: example.com/[a
+`,
+			expectedError: true,
+		},
+		"InvalidVersion": {
+			content: `banned:
+  - module: example.com/a
+    versions: ">=1.2"
+`,
+			expectedError: true,
+		},
+		"ShorthandVersion": {
+			content: `banned:
+  - module: example.com/a
+    versions: <v2
+`,
+			expectedError: true,
+		},
+		"UnknownField": {
+			content: `denied:
+  - module: example.com/a
+`,
+			expectedError: true,
+		},
+	}
+
+	for name := range testcases {
+		testcase := testcases[name]
+		t.Run(name, func(t *testing.T) {
+			log := testutil.TestLogger(t)
+			path := filepath.Join(t.TempDir(), "policy.yaml")
+			require.NoError(t, ioutil.WriteFile(path, []byte(testcase.content), 0o600))
+
+			p, err := ParsePolicy(log, path)
+			if testcase.expectedError {
+				assert.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, testcase.expectedPolicy, p)
+			}
+		})
+	}
+}
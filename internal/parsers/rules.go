@@ -0,0 +1,110 @@
+package parsers
+
+import (
+	"errors"
+	"os"
+
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v3"
+
+	"github.com/Helcaraxan/gomod/internal/depgraph"
+	"github.com/Helcaraxan/gomod/internal/logger"
+	"github.com/Helcaraxan/gomod/internal/query"
+)
+
+// rulesFile is the content of a rules file as written by users.
+type rulesFile struct {
+	Rules []rulesFileRule `yaml:"rules"`
+}
+
+type rulesFileRule struct {
+	Name     string `yaml:"name"`
+	Level    string `yaml:"level"`
+	Query    string `yaml:"query"`
+	SubsetOf string `yaml:"subset_of"`
+	From     string `yaml:"from"`
+}
+
+// ParseRules reads the rules that a dependency graph should satisfy from a YAML file. Each rule has
+// a query which must select no nodes or, when 'subset_of' is set, only nodes that are also selected
+// by that second query.
+func ParseRules(dl *logger.Builder, path string) ([]*depgraph.Rule, error) {
+	log := dl.Domain(logger.InitDomain)
+
+	f, err := os.Open(path)
+	if err != nil {
+		log.Error("Could not open rules file.", zap.String("path", path), zap.Error(err))
+		return nil, err
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	var raw rulesFile
+	decoder := yaml.NewDecoder(f)
+	decoder.KnownFields(true)
+	if err = decoder.Decode(&raw); err != nil {
+		log.Error("Could not parse rules file.", zap.String("path", path), zap.Error(err))
+		return nil, errors.New("invalid rules file")
+	}
+
+	names := map[string]bool{}
+	rules := make([]*depgraph.Rule, 0, len(raw.Rules))
+	for idx, r := range raw.Rules {
+		rule, err := parseRule(dl, idx+1, r)
+		if err != nil {
+			return nil, err
+		}
+		if names[rule.Name] {
+			log.Error("Found several rules with the same name.", zap.Int("rule", idx+1), zap.String("name", rule.Name))
+			return nil, errors.New("invalid rule")
+		}
+		names[rule.Name] = true
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+func parseRule(dl *logger.Builder, number int, raw rulesFileRule) (*depgraph.Rule, error) {
+	log := dl.Domain(logger.InitDomain)
+
+	rule := &depgraph.Rule{Name: raw.Name}
+	if rule.Name == "" {
+		log.Error("Rule does not have a 'name'.", zap.Int("rule", number))
+		return nil, errors.New("invalid rule")
+	}
+
+	switch raw.Level {
+	case "", "modules":
+		rule.Level = depgraph.LevelModules
+	case "packages":
+		rule.Level = depgraph.LevelPackages
+	default:
+		log.Error("Could not parse 'level' of rule. Accepted values are 'modules' and 'packages'.", zap.String("rule", raw.Name), zap.String("value", raw.Level))
+		return nil, errors.New("invalid rule")
+	}
+
+	if raw.Query == "" {
+		log.Error("Rule does not have a 'query'.", zap.String("rule", raw.Name))
+		return nil, errors.New("invalid rule")
+	}
+	for _, q := range []struct {
+		raw    string
+		target *query.Expr
+	}{
+		{raw: raw.Query, target: &rule.Query},
+		{raw: raw.SubsetOf, target: &rule.Allowed},
+		{raw: raw.From, target: &rule.From},
+	} {
+		if q.raw == "" {
+			continue
+		}
+		expr, err := query.Parse(dl, q.raw)
+		if err != nil {
+			log.Error("Could not parse query of rule.", zap.String("rule", raw.Name), zap.String("query", q.raw), zap.Error(err))
+			return nil, errors.New("invalid rule")
+		}
+		*q.target = expr
+	}
+	return rule, nil
+}
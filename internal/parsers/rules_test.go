@@ -0,0 +1,119 @@
+package parsers
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Helcaraxan/gomod/internal/depgraph"
+	"github.com/Helcaraxan/gomod/internal/testutil"
+)
+
+func TestRules(t *testing.T) {
+	type expectedRule struct {
+		name    string
+		level   depgraph.Level
+		hasFrom bool
+		hasSet  bool
+	}
+
+	testcases := map[string]struct {
+		content       string
+		expectedRules []expectedRule
+		expectedError bool
+	}{
+		"Empty": {
+			content:       "rules: []\n",
+			expectedRules: []expectedRule{},
+		},
+		"Rules": {
+			content: `rules:
+  - name: api does not use storage
+    level: packages
+    from: ourorg.com/svc/api
+    query: deps(ourorg.com/svc/api) inter ourorg.com/svc/storage
+  - name: approved logging
+    query: deps(ourorg.com/svc) inter go.uber.org/**
+    subset_of: go.uber.org/zap
+`,
+			expectedRules: []expectedRule{
+				{name: "api does not use storage", level: depgraph.LevelPackages, hasFrom: true},
+				{name: "approved logging", level: depgraph.LevelModules, hasSet: true},
+			},
+		},
+		"MissingName": {
+			content: `rules:
+  - query: example.com/**
+`,
+			expectedError: true,
+		},
+		"MissingQuery": {
+			content: `rules:
+  - name: nothing
+`,
+			expectedError: true,
+		},
+		"InvalidLevel": {
+			content: `rules:
+  - name: files
+    level: files
+    query: example.com/**
+`,
+			expectedError: true,
+		},
+		"InvalidQuery": {
+			content: `rules:
+  - name: broken
+    query: deps(example.com
+`,
+			expectedError: true,
+		},
+		"DuplicateName": {
+			content: `rules:
+  - name: twice
+    query: example.com/a
+  - name: twice
+    query: example.com/b
+`,
+			expectedError: true,
+		},
+		"UnknownField": {
+			content: `rules:
+  - name: typo
+    querry: example.com/**
+`,
+			expectedError: true,
+		},
+	}
+
+	for name := range testcases {
+		testcase := testcases[name]
+		t.Run(name, func(t *testing.T) {
+			log := testutil.TestLogger(t)
+			path := filepath.Join(t.TempDir(), "rules.yaml")
+			require.NoError(t, ioutil.WriteFile(path, []byte(testcase.content), 0o600))
+
+			rules, err := ParseRules(log, path)
+			if testcase.expectedError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			actual := make([]expectedRule, 0, len(rules))
+			for _, rule := range rules {
+				assert.NotNil(t, rule.Query)
+				actual = append(actual, expectedRule{
+					name:    rule.Name,
+					level:   rule.Level,
+					hasFrom: rule.From != nil,
+					hasSet:  rule.Allowed != nil,
+				})
+			}
+			assert.Equal(t, testcase.expectedRules, actual)
+		})
+	}
+}
@@ -2,8 +2,10 @@ package parsers
 
 import (
 	"errors"
+	"strconv"
 	"strings"
 
+	"github.com/bmatcuk/doublestar/v3"
 	"go.uber.org/zap"
 
 	"github.com/Helcaraxan/gomod/internal/logger"
@@ -12,7 +14,24 @@ import (
 
 func ParseStyleConfiguration(log *logger.Logger, config string) (*printer.StyleOptions, error) {
 	styleOptions := &printer.StyleOptions{}
-	for _, setting := range strings.Split(config, ",") {
+	if err := ApplyStyleConfiguration(log, styleOptions, config); err != nil {
+		return nil, err
+	}
+	return styleOptions, nil
+}
+
+// ApplyStyleConfiguration sets the options of a '<option>=<value>[,<option>=<value>]' string on top
+// of the given style options, such as those read from a style file. Commas inside the braces or
+// brackets of a glob pattern, as in 'same_rank={foo,bar}/**', do not separate options. As for all
+// other options, 'same_rank' patterns replace those of the given style options instead of being
+// added to them.
+func ApplyStyleConfiguration(log *logger.Logger, styleOptions *printer.StyleOptions, config string) error {
+	settings, err := splitStyleSettings(log, config)
+	if err != nil {
+		return err
+	}
+	var sameRankSet bool
+	for _, setting := range settings {
 		if setting == "" {
 			continue
 		}
@@ -24,39 +43,227 @@ func ParseStyleConfiguration(log *logger.Logger, config string) (*printer.StyleO
 			configValue = setting[valueIdx+1:]
 		}
 		configKey = strings.ToLower(strings.TrimSpace(configKey))
-		configValue = strings.ToLower(strings.TrimSpace(configValue))
-
-		var err error
-		switch configKey {
-		case "scale_nodes":
-			err = parseStyleScaleNodes(log, styleOptions, configValue)
-		case "cluster":
-			err = parseStyleCluster(log, styleOptions, configValue)
-		default:
-			log.Error("Skipping unknown style option.", zap.String("option", configKey))
-			err = errors.New("invalid config")
+		configValue = strings.TrimSpace(configValue)
+
+		if configKey == "same_rank" && !sameRankSet {
+			styleOptions.SameRank = nil
+			sameRankSet = true
 		}
-		if err != nil {
-			return nil, err
+
+		if err := applyStyleOption(log, styleOptions, configKey, configValue); err != nil {
+			return err
 		}
 	}
-	return styleOptions, nil
+	return nil
+}
+
+// splitStyleSettings splits a style configuration on the commas that are not part of a glob's
+// alternatives or character classes.
+func splitStyleSettings(log *logger.Logger, config string) ([]string, error) {
+	var settings []string
+	var braces int
+	var inClass bool
+	start := 0
+	for idx := 0; idx < len(config); idx++ {
+		switch c := config[idx]; {
+		case c == '\\':
+			idx++
+		case inClass:
+			inClass = c != ']'
+		case c == '[':
+			inClass = true
+		case c == '{':
+			braces++
+		case c == '}' && braces > 0:
+			braces--
+		case c == ',' && braces == 0:
+			settings = append(settings, config[start:idx])
+			start = idx + 1
+		}
+	}
+	if braces > 0 || inClass {
+		log.Error("Could not parse style configuration. A glob pattern has an unterminated '{' or '['.", zap.String("value", config))
+		return nil, errors.New("invalid config")
+	}
+	return append(settings, config[start:]), nil
+}
+
+func applyStyleOption(log *logger.Logger, styleOptions *printer.StyleOptions, key string, value string) error {
+	switch key {
+	case "scale_nodes":
+		return parseStyleScaleNodes(log, styleOptions, value)
+	case "cluster":
+		return parseStyleCluster(log, styleOptions, value)
+	case "legend":
+		return parseStyleLegend(log, styleOptions, value)
+	case "color_by":
+		return parseStyleColourBy(log, styleOptions, value)
+	case "rankdir":
+		return parseStyleRankDir(log, styleOptions, value)
+	case "ranksep":
+		return parseStyleRankSep(log, styleOptions, value)
+	case "splines":
+		return parseStyleSplines(log, styleOptions, value)
+	case "edge_weights":
+		return parseStyleEdgeWeights(log, styleOptions, value)
+	case "font":
+		styleOptions.Font = value
+		return nil
+	case "same_rank":
+		return parseStyleSameRank(log, styleOptions, value)
+	case "pin_main":
+		return parseStylePinMain(log, styleOptions, value)
+	case "max_rank":
+		return parseStyleMaxRank(log, styleOptions, value)
+	default:
+		log.Error("Skipping unknown style option.", zap.String("option", key))
+		return errors.New("invalid config")
+	}
 }
 
 func parseStyleScaleNodes(log *logger.Logger, styleOptions *printer.StyleOptions, raw string) error {
 	switch strings.ToLower(raw) {
-	case "", "true", "on", "yes":
-		styleOptions.ScaleNodes = true
+	case "", "true", "on", "yes", "edges":
+		styleOptions.ScaleNodes = printer.ScaleByEdges
 	case "false", "off", "no":
-		styleOptions.ScaleNodes = false
+		styleOptions.ScaleNodes = printer.ScaleOff
+	case "files":
+		styleOptions.ScaleNodes = printer.ScaleByFiles
+	case "loc":
+		styleOptions.ScaleNodes = printer.ScaleByLines
+	case "packages":
+		styleOptions.ScaleNodes = printer.ScaleByPackages
 	default:
-		log.Error("Could not set 'scale_nodes' style. Accepted values are 'true' and 'false'.", zap.String("value", raw))
+		log.Error(
+			"Could not set 'scale_nodes' style. Accepted values are 'true', 'false', 'edges', 'files', 'loc' and 'packages'.",
+			zap.String("value", raw),
+		)
 		return errors.New("invalid 'scale_nodes' value")
 	}
 	return nil
 }
 
+func parseStyleLegend(log *logger.Logger, styleOptions *printer.StyleOptions, raw string) error {
+	switch strings.ToLower(raw) {
+	case "", "true", "on", "yes":
+		styleOptions.Legend = true
+	case "false", "off", "no":
+		styleOptions.Legend = false
+	default:
+		log.Error("Could not set 'legend' style. Accepted values are 'true' and 'false'.", zap.String("value", raw))
+		return errors.New("invalid 'legend' value")
+	}
+	return nil
+}
+
+func parseStyleColourBy(log *logger.Logger, styleOptions *printer.StyleOptions, raw string) error {
+	switch strings.ToLower(raw) {
+	case "name":
+		styleOptions.ColourBy = printer.ColourByName
+	case "age":
+		styleOptions.ColourBy = printer.ColourByAge
+	case "backlog":
+		styleOptions.ColourBy = printer.ColourByBacklog
+	case "rdeps":
+		styleOptions.ColourBy = printer.ColourByRdeps
+	case "packages":
+		styleOptions.ColourBy = printer.ColourByPackages
+	default:
+		log.Error(
+			"Could not set 'color_by' style. Accepted values are 'name', 'age', 'backlog', 'rdeps' and 'packages'.",
+			zap.String("value", raw),
+		)
+		return errors.New("invalid 'color_by' value")
+	}
+	return nil
+}
+
+func parseStyleRankDir(log *logger.Logger, styleOptions *printer.StyleOptions, raw string) error {
+	switch direction := strings.ToUpper(raw); direction {
+	case "TB", "BT", "LR", "RL":
+		styleOptions.Direction = direction
+	default:
+		log.Error("Could not set 'rankdir' style. Accepted values are 'TB', 'BT', 'LR' and 'RL'.", zap.String("value", raw))
+		return errors.New("invalid 'rankdir' value")
+	}
+	return nil
+}
+
+func parseStyleRankSep(log *logger.Logger, styleOptions *printer.StyleOptions, raw string) error {
+	rankSep, err := strconv.ParseFloat(raw, 64)
+	if err != nil || rankSep <= 0 {
+		log.Error("Could not set 'ranksep' style. Expected a positive number of inches.", zap.String("value", raw))
+		return errors.New("invalid 'ranksep' value")
+	}
+	styleOptions.RankSep = rankSep
+	return nil
+}
+
+func parseStyleSplines(log *logger.Logger, styleOptions *printer.StyleOptions, raw string) error {
+	switch splines := strings.ToLower(raw); splines {
+	case "ortho", "polyline", "spline", "line", "curved", "none":
+		styleOptions.Splines = splines
+	default:
+		log.Error(
+			"Could not set 'splines' style. Accepted values are 'ortho', 'polyline', 'spline', 'line', 'curved' and 'none'.",
+			zap.String("value", raw),
+		)
+		return errors.New("invalid 'splines' value")
+	}
+	return nil
+}
+
+func parseStyleEdgeWeights(log *logger.Logger, styleOptions *printer.StyleOptions, raw string) error {
+	switch strings.ToLower(raw) {
+	case "", "true", "on", "yes":
+		styleOptions.EdgeWeights = true
+	case "false", "off", "no":
+		styleOptions.EdgeWeights = false
+	default:
+		log.Error("Could not set 'edge_weights' style. Accepted values are 'true' and 'false'.", zap.String("value", raw))
+		return errors.New("invalid 'edge_weights' value")
+	}
+	return nil
+}
+
+func parseStyleSameRank(log *logger.Logger, styleOptions *printer.StyleOptions, raw string) error {
+	if raw == "" {
+		log.Error("Could not set 'same_rank' style. Expected a glob pattern matching the nodes to place on the same rank.")
+		return errors.New("invalid 'same_rank' value")
+	}
+	if _, err := doublestar.Match(raw, raw); err != nil {
+		log.Error("Could not set 'same_rank' style. Invalid glob pattern.", zap.String("value", raw), zap.Error(err))
+		return errors.New("invalid 'same_rank' value")
+	}
+	styleOptions.SameRank = append(styleOptions.SameRank, raw)
+	return nil
+}
+
+func parseStylePinMain(log *logger.Logger, styleOptions *printer.StyleOptions, raw string) error {
+	switch strings.ToLower(raw) {
+	case "", "true", "on", "yes":
+		styleOptions.PinMain = true
+	case "false", "off", "no":
+		styleOptions.PinMain = false
+	default:
+		log.Error("Could not set 'pin_main' style. Accepted values are 'true' and 'false'.", zap.String("value", raw))
+		return errors.New("invalid 'pin_main' value")
+	}
+	return nil
+}
+
+func parseStyleMaxRank(log *logger.Logger, styleOptions *printer.StyleOptions, raw string) error {
+	maxRank, err := strconv.Atoi(raw)
+	if err != nil || maxRank < 0 {
+		log.Error("Could not set 'max_rank' style. Expected a positive number of ranks or zero for no limit.", zap.String("value", raw))
+		return errors.New("invalid 'max_rank' value")
+	}
+	styleOptions.MaxRank = maxRank
+	return nil
+}
+
 func parseStyleCluster(log *logger.Logger, styleOptions *printer.StyleOptions, raw string) error {
+	styleOptions.ClusterParents = false
 	switch strings.ToLower(raw) {
 	case "off", "false", "no":
 		styleOptions.Cluster = printer.Off
@@ -64,8 +271,19 @@ func parseStyleCluster(log *logger.Logger, styleOptions *printer.StyleOptions, r
 		styleOptions.Cluster = printer.Shared
 	case "full":
 		styleOptions.Cluster = printer.Full
+	case "module":
+		styleOptions.Cluster = printer.Parent
+	case "module+shared":
+		styleOptions.Cluster = printer.Shared
+		styleOptions.ClusterParents = true
+	case "module+full":
+		styleOptions.Cluster = printer.Full
+		styleOptions.ClusterParents = true
 	default:
-		log.Error("Could not set 'cluster' style. Accepted values are 'off', 'shared' and 'full'.", zap.String("value", raw))
+		log.Error(
+			"Could not set 'cluster' style. Accepted values are 'off', 'shared', 'full', 'module', 'module+shared' and 'module+full'.",
+			zap.String("value", raw),
+		)
 		return errors.New("invalid 'cluster' value")
 	}
 	return nil
@@ -0,0 +1,134 @@
+package parsers
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v3"
+
+	"github.com/Helcaraxan/gomod/internal/logger"
+	"github.com/Helcaraxan/gomod/internal/printer"
+)
+
+// ParseStyleFile reads style options from a YAML file. Its top-level keys are, regardless of their
+// case, those accepted by ParseStyleConfiguration, with 'same_rank' also accepting a list of
+// patterns, together with 'nodes' listing attribute overrides for the nodes matching a pattern and
+// 'format' setting the output format. The latter is nil if the file does not set it.
+func ParseStyleFile(log *logger.Logger, path string) (*printer.StyleOptions, *printer.Format, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		log.Error("Could not read style file.", zap.String("path", path), zap.Error(err))
+		return nil, nil, err
+	}
+
+	var document yaml.Node
+	if err = yaml.Unmarshal(content, &document); err != nil {
+		log.Error("Could not parse style file.", zap.String("path", path), zap.Error(err))
+		return nil, nil, errors.New("invalid style file")
+	}
+
+	styleOptions := &printer.StyleOptions{}
+	if len(document.Content) == 0 {
+		return styleOptions, nil, nil
+	}
+	root := document.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil, nil, styleFileError(log, path, "", root.Line, "expected a mapping of style options")
+	}
+
+	var format *printer.Format
+	for idx := 0; idx+1 < len(root.Content); idx += 2 {
+		key, value := root.Content[idx], root.Content[idx+1]
+		key.Value = strings.ToLower(strings.TrimSpace(key.Value))
+		switch key.Value {
+		case "nodes":
+			if styleOptions.NodeOverrides, err = parseNodeOverrides(log, path, value); err != nil {
+				return nil, nil, err
+			}
+		case "format":
+			if value.Kind != yaml.ScalarNode {
+				return nil, nil, styleFileError(log, path, key.Value, value.Line, "expected a single value")
+			}
+			f, err := ParseFormat(log, value.Value)
+			if err != nil {
+				return nil, nil, styleFileError(log, path, key.Value, value.Line, "invalid value")
+			}
+			format = &f
+		case "same_rank":
+			patterns := []*yaml.Node{value}
+			if value.Kind == yaml.SequenceNode {
+				patterns = value.Content
+			}
+			for _, pattern := range patterns {
+				if pattern.Kind != yaml.ScalarNode {
+					return nil, nil, styleFileError(log, path, key.Value, pattern.Line, "expected a glob pattern or a list of them")
+				}
+				if err = applyStyleOption(log, styleOptions, key.Value, pattern.Value); err != nil {
+					return nil, nil, styleFileError(log, path, key.Value, pattern.Line, "invalid value")
+				}
+			}
+		default:
+			if value.Kind != yaml.ScalarNode {
+				return nil, nil, styleFileError(log, path, key.Value, value.Line, "expected a single value")
+			}
+			if err = applyStyleOption(log, styleOptions, key.Value, value.Value); err != nil {
+				return nil, nil, styleFileError(log, path, key.Value, value.Line, "invalid option or value")
+			}
+		}
+	}
+	return styleOptions, format, nil
+}
+
+func parseNodeOverrides(log *logger.Logger, path string, raw *yaml.Node) ([]printer.NodeOverride, error) {
+	if raw.Kind != yaml.SequenceNode {
+		return nil, styleFileError(log, path, "nodes", raw.Line, "expected a list of node overrides")
+	}
+
+	overrides := make([]printer.NodeOverride, 0, len(raw.Content))
+	for _, item := range raw.Content {
+		if item.Kind != yaml.MappingNode {
+			return nil, styleFileError(log, path, "nodes", item.Line, "expected a mapping with 'match' and 'attributes'")
+		}
+
+		override := printer.NodeOverride{Attributes: map[string]string{}}
+		for idx := 0; idx+1 < len(item.Content); idx += 2 {
+			key, value := item.Content[idx], item.Content[idx+1]
+			switch key.Value {
+			case "match":
+				if value.Kind != yaml.ScalarNode || value.Value == "" {
+					return nil, styleFileError(log, path, "nodes.match", value.Line, "expected a glob pattern")
+				}
+				override.Pattern = value.Value
+			case "attributes":
+				if value.Kind != yaml.MappingNode {
+					return nil, styleFileError(log, path, "nodes.attributes", value.Line, "expected a mapping of DOT attributes")
+				}
+				for attrIdx := 0; attrIdx+1 < len(value.Content); attrIdx += 2 {
+					name, attribute := value.Content[attrIdx], value.Content[attrIdx+1]
+					if attribute.Kind != yaml.ScalarNode {
+						return nil, styleFileError(log, path, "nodes.attributes."+name.Value, attribute.Line, "expected a single value")
+					}
+					override.Attributes[name.Value] = attribute.Value
+				}
+			default:
+				return nil, styleFileError(log, path, "nodes."+key.Value, key.Line, "unknown field")
+			}
+		}
+		if override.Pattern == "" || len(override.Attributes) == 0 {
+			return nil, styleFileError(log, path, "nodes", item.Line, "node overrides require both 'match' and 'attributes'")
+		}
+		overrides = append(overrides, override)
+	}
+	return overrides, nil
+}
+
+func styleFileError(log *logger.Logger, path string, key string, line int, reason string) error {
+	log.Error("Invalid style file.", zap.String("path", path), zap.String("key", key), zap.Int("line", line), zap.String("reason", reason))
+	if key == "" {
+		return fmt.Errorf("%s:%d: %s", path, line, reason)
+	}
+	return fmt.Errorf("%s:%d: '%s': %s", path, line, key, reason)
+}
@@ -0,0 +1,189 @@
+package parsers
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Helcaraxan/gomod/internal/printer"
+	"github.com/Helcaraxan/gomod/internal/testutil"
+)
+
+func TestStyleFile(t *testing.T) {
+	mermaid := printer.FormatMermaid
+
+	testcases := map[string]struct {
+		content        string
+		expectedConfig *printer.StyleOptions
+		expectedFormat *printer.Format
+		expectedError  string
+	}{
+		"Empty": {
+			content:        "",
+			expectedConfig: &printer.StyleOptions{},
+		},
+		"Full": {
+			content: `format: mermaid
+scale_nodes: true
+cluster: module+shared
+legend: yes
+color_by: age
+rankdir: LR
+ranksep: 0.8
+splines: polyline
+font: Fira Sans
+nodes:
+  - match: github.com/our/**
+    attributes:
+      shape: ellipse
+      fillcolor: "#1f77b4"
+`,
+			expectedConfig: &printer.StyleOptions{
+				ScaleNodes:     printer.ScaleByEdges,
+				Cluster:        printer.Shared,
+				ClusterParents: true,
+				Legend:         true,
+				ColourBy:       printer.ColourByAge,
+				Direction:      "LR",
+				RankSep:        0.8,
+				Splines:        "polyline",
+				Font:           "Fira Sans",
+				NodeOverrides: []printer.NodeOverride{
+					{Pattern: "github.com/our/**", Attributes: map[string]string{"shape": "ellipse", "fillcolor": "#1f77b4"}},
+				},
+			},
+			expectedFormat: &mermaid,
+		},
+		"SameRankList": {
+			content: `same_rank:
+  - github.com/aws/**
+  - golang.org/x/*
+pin_main: true
+max_rank: 4
+`,
+			expectedConfig: &printer.StyleOptions{
+				SameRank: []string{"github.com/aws/**", "golang.org/x/*"},
+				PinMain:  true,
+				MaxRank:  4,
+			},
+		},
+		"UpperCaseKeys": {
+			content: "Legend: true\nRANKDIR: LR\nSame_Rank: github.com/aws/**\n",
+			expectedConfig: &printer.StyleOptions{
+				Legend:    true,
+				Direction: "LR",
+				SameRank:  []string{"github.com/aws/**"},
+			},
+		},
+		"InvalidSameRankItem": {
+			content:       "same_rank:\n  - github.com/aws/**\n  - [nested]\n",
+			expectedError: ":3: 'same_rank': expected a glob pattern or a list of them",
+		},
+		"UnknownKey": {
+			content:       "legend: true\nshape: round\n",
+			expectedError: ":2: 'shape': invalid option or value",
+		},
+		"InvalidValue": {
+			content:       "cluster: shared\n\nrankdir: diagonal\n",
+			expectedError: ":3: 'rankdir': invalid option or value",
+		},
+		"InvalidFormat": {
+			content:       "format: png\n",
+			expectedError: ":1: 'format': invalid value",
+		},
+		"NestedValue": {
+			content:       "legend:\n  enabled: true\n",
+			expectedError: ":2: 'legend': expected a single value",
+		},
+		"UnknownNodeField": {
+			content:       "nodes:\n  - match: github.com/our/**\n    colour: red\n",
+			expectedError: ":3: 'nodes.colour': unknown field",
+		},
+		"NodeWithoutAttributes": {
+			content:       "nodes:\n  - match: github.com/our/**\n",
+			expectedError: ":2: 'nodes': node overrides require both 'match' and 'attributes'",
+		},
+		"NotAMapping": {
+			content:       "- legend\n",
+			expectedError: ":1: expected a mapping of style options",
+		},
+	}
+
+	for name := range testcases {
+		testcase := testcases[name]
+		t.Run(name, func(t *testing.T) {
+			log := testutil.TestLogger(t)
+			path := filepath.Join(t.TempDir(), "style.yaml")
+			require.NoError(t, ioutil.WriteFile(path, []byte(testcase.content), 0o600))
+
+			config, format, err := ParseStyleFile(log.Log(), path)
+			if testcase.expectedError != "" {
+				require.Error(t, err)
+				assert.Equal(t, path+testcase.expectedError, err.Error())
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, testcase.expectedConfig, config)
+				assert.Equal(t, testcase.expectedFormat, format)
+			}
+		})
+	}
+}
+
+func TestStyleFileWithInlineStyle(t *testing.T) {
+	const content = `same_rank:
+  - github.com/aws/**
+  - golang.org/x/*
+rankdir: LR
+`
+
+	testcases := map[string]struct {
+		style          string
+		expectedConfig *printer.StyleOptions
+	}{
+		"NoInlineStyle": {
+			style: "",
+			expectedConfig: &printer.StyleOptions{
+				SameRank:  []string{"github.com/aws/**", "golang.org/x/*"},
+				Direction: "LR",
+			},
+		},
+		"OtherOption": {
+			style: "rankdir=TB",
+			expectedConfig: &printer.StyleOptions{
+				SameRank:  []string{"github.com/aws/**", "golang.org/x/*"},
+				Direction: "TB",
+			},
+		},
+		"SameRankReplacesFile": {
+			style: "same_rank=github.com/our/**",
+			expectedConfig: &printer.StyleOptions{
+				SameRank:  []string{"github.com/our/**"},
+				Direction: "LR",
+			},
+		},
+		"MultipleSameRank": {
+			style: "same_rank=github.com/our/**,rankdir=TB,SAME_RANK={foo,bar}/*",
+			expectedConfig: &printer.StyleOptions{
+				SameRank:  []string{"github.com/our/**", "{foo,bar}/*"},
+				Direction: "TB",
+			},
+		},
+	}
+
+	for name := range testcases {
+		testcase := testcases[name]
+		t.Run(name, func(t *testing.T) {
+			log := testutil.TestLogger(t)
+			path := filepath.Join(t.TempDir(), "style.yaml")
+			require.NoError(t, ioutil.WriteFile(path, []byte(content), 0o600))
+
+			config, _, err := ParseStyleFile(log.Log(), path)
+			require.NoError(t, err)
+			require.NoError(t, ApplyStyleConfiguration(log.Log(), config, testcase.style))
+			assert.Equal(t, testcase.expectedConfig, config)
+		})
+	}
+}
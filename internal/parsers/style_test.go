@@ -22,31 +22,51 @@ func TestVisualConfig(t *testing.T) {
 		},
 		"ScaleNodesFalse": {
 			optionValue:    "scale_nodes=false",
-			expectedConfig: &printer.StyleOptions{ScaleNodes: false},
+			expectedConfig: &printer.StyleOptions{ScaleNodes: printer.ScaleOff},
 		},
 		"ScaleNodesNo": {
 			optionValue:    "scale_nodes=no",
-			expectedConfig: &printer.StyleOptions{ScaleNodes: false},
+			expectedConfig: &printer.StyleOptions{ScaleNodes: printer.ScaleOff},
 		},
 		"ScaleNodesOff": {
 			optionValue:    "scale_nodes=off",
-			expectedConfig: &printer.StyleOptions{ScaleNodes: false},
+			expectedConfig: &printer.StyleOptions{ScaleNodes: printer.ScaleOff},
 		},
 		"ScaleNodesEmpty": {
 			optionValue:    "scale_nodes",
-			expectedConfig: &printer.StyleOptions{ScaleNodes: true},
+			expectedConfig: &printer.StyleOptions{ScaleNodes: printer.ScaleByEdges},
 		},
 		"ScaleNodesTrue": {
 			optionValue:    "scale_nodes=true",
-			expectedConfig: &printer.StyleOptions{ScaleNodes: true},
+			expectedConfig: &printer.StyleOptions{ScaleNodes: printer.ScaleByEdges},
 		},
 		"ScaleNodesYes": {
 			optionValue:    "scale_nodes=yes",
-			expectedConfig: &printer.StyleOptions{ScaleNodes: true},
+			expectedConfig: &printer.StyleOptions{ScaleNodes: printer.ScaleByEdges},
 		},
 		"ScaleNodesOn": {
 			optionValue:    "scale_nodes=on",
-			expectedConfig: &printer.StyleOptions{ScaleNodes: true},
+			expectedConfig: &printer.StyleOptions{ScaleNodes: printer.ScaleByEdges},
+		},
+		"ScaleNodesEdges": {
+			optionValue:    "scale_nodes=edges",
+			expectedConfig: &printer.StyleOptions{ScaleNodes: printer.ScaleByEdges},
+		},
+		"ScaleNodesFiles": {
+			optionValue:    "scale_nodes=files",
+			expectedConfig: &printer.StyleOptions{ScaleNodes: printer.ScaleByFiles},
+		},
+		"ScaleNodesLines": {
+			optionValue:    "scale_nodes=loc",
+			expectedConfig: &printer.StyleOptions{ScaleNodes: printer.ScaleByLines},
+		},
+		"ScaleNodesPackages": {
+			optionValue:    "scale_nodes=packages",
+			expectedConfig: &printer.StyleOptions{ScaleNodes: printer.ScaleByPackages},
+		},
+		"ScaleNodesInvalid": {
+			optionValue:   "scale_nodes=size",
+			expectedError: true,
 		},
 		"ClusterFalse": {
 			optionValue:    "cluster=false",
@@ -84,18 +104,86 @@ func TestVisualConfig(t *testing.T) {
 			optionValue:    "cluster=full",
 			expectedConfig: &printer.StyleOptions{Cluster: printer.Full},
 		},
+		"ClusterModule": {
+			optionValue:    "cluster=module",
+			expectedConfig: &printer.StyleOptions{Cluster: printer.Parent},
+		},
+		"ClusterModuleShared": {
+			optionValue:    "cluster=module+shared",
+			expectedConfig: &printer.StyleOptions{Cluster: printer.Shared, ClusterParents: true},
+		},
+		"ClusterModuleFull": {
+			optionValue:    "cluster=Module+Full",
+			expectedConfig: &printer.StyleOptions{Cluster: printer.Full, ClusterParents: true},
+		},
+		"LegendEmpty": {
+			optionValue:    "legend",
+			expectedConfig: &printer.StyleOptions{Legend: true},
+		},
+		"LegendOff": {
+			optionValue:    "legend=off",
+			expectedConfig: &printer.StyleOptions{Legend: false},
+		},
+		"EdgeWeightsEmpty": {
+			optionValue:    "edge_weights",
+			expectedConfig: &printer.StyleOptions{EdgeWeights: true},
+		},
+		"EdgeWeightsOff": {
+			optionValue:    "edge_weights=off",
+			expectedConfig: &printer.StyleOptions{EdgeWeights: false},
+		},
+		"ColourByAge": {
+			optionValue:    "color_by=age",
+			expectedConfig: &printer.StyleOptions{ColourBy: printer.ColourByAge},
+		},
+		"ColourByBacklog": {
+			optionValue:    "color_by=Backlog",
+			expectedConfig: &printer.StyleOptions{ColourBy: printer.ColourByBacklog},
+		},
+		"ColourByRdeps": {
+			optionValue:    "color_by=rdeps",
+			expectedConfig: &printer.StyleOptions{ColourBy: printer.ColourByRdeps},
+		},
+		"ColourByPackages": {
+			optionValue:    "color_by=packages",
+			expectedConfig: &printer.StyleOptions{ColourBy: printer.ColourByPackages},
+		},
+		"Layout": {
+			optionValue: "rankdir=lr,ranksep=1.5,splines=Polyline,font=Fira Sans",
+			expectedConfig: &printer.StyleOptions{
+				Direction: "LR",
+				RankSep:   1.5,
+				Splines:   "polyline",
+				Font:      "Fira Sans",
+			},
+		},
+		"RankConstraints": {
+			optionValue: "same_rank=github.com/aws/**,same_rank=golang.org/x/*,pin_main,max_rank=3",
+			expectedConfig: &printer.StyleOptions{
+				SameRank: []string{"github.com/aws/**", "golang.org/x/*"},
+				PinMain:  true,
+				MaxRank:  3,
+			},
+		},
+		"SameRankAlternatives": {
+			optionValue: "same_rank={github.com/aws,golang.org/x}/**,same_rank=example.com/[a,b],pin_main",
+			expectedConfig: &printer.StyleOptions{
+				SameRank: []string{"{github.com/aws,golang.org/x}/**", "example.com/[a,b]"},
+				PinMain:  true,
+			},
+		},
 		"AllConfigsSimple": {
 			optionValue: "cluster=true,scale_nodes=true",
 			expectedConfig: &printer.StyleOptions{
 				Cluster:    printer.Shared,
-				ScaleNodes: true,
+				ScaleNodes: printer.ScaleByEdges,
 			},
 		},
 		"AllConfigsComplex": {
 			optionValue: "cluster=True , scale_nodes = tRuE",
 			expectedConfig: &printer.StyleOptions{
 				Cluster:    printer.Shared,
-				ScaleNodes: true,
+				ScaleNodes: printer.ScaleByEdges,
 			},
 		},
 		"UnknownConfig": {
@@ -110,6 +198,54 @@ func TestVisualConfig(t *testing.T) {
 			optionValue:   "cluster=foo",
 			expectedError: true,
 		},
+		"UnknownLegendValue": {
+			optionValue:   "legend=foo",
+			expectedError: true,
+		},
+		"UnknownEdgeWeightsValue": {
+			optionValue:   "edge_weights=heavy",
+			expectedError: true,
+		},
+		"UnknownRankDirValue": {
+			optionValue:   "rankdir=diagonal",
+			expectedError: true,
+		},
+		"NegativeRankSepValue": {
+			optionValue:   "ranksep=-1",
+			expectedError: true,
+		},
+		"UnknownSplinesValue": {
+			optionValue:   "splines=wiggly",
+			expectedError: true,
+		},
+		"EmptySameRankValue": {
+			optionValue:   "same_rank",
+			expectedError: true,
+		},
+		"InvalidSameRankPattern": {
+			optionValue:   "same_rank=github.com/[a",
+			expectedError: true,
+		},
+		"UnterminatedSameRankAlternatives": {
+			optionValue:   "same_rank={github.com/aws,pin_main",
+			expectedError: true,
+		},
+		"UnknownPinMainValue": {
+			optionValue:   "pin_main=top",
+			expectedError: true,
+		},
+		"NegativeMaxRankValue": {
+			optionValue:   "max_rank=-2",
+			expectedError: true,
+		},
+		"EmptyColourByValue": {
+			optionValue:   "color_by",
+			expectedError: true,
+		},
+		"UnknownColourByValue": {
+			optionValue:   "color_by=size",
+			expectedError: true,
+		},
 	}
 
 	for name := range testcases {
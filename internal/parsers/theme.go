@@ -0,0 +1,140 @@
+package parsers
+
+import (
+	"errors"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v3"
+
+	"github.com/Helcaraxan/gomod/internal/depgraph"
+	"github.com/Helcaraxan/gomod/internal/logger"
+	"github.com/Helcaraxan/gomod/internal/printer"
+)
+
+// themeFile is the content of a theme file as written by users.
+type themeFile struct {
+	Rules []themeFileRule `yaml:"rules"`
+}
+
+type themeFileRule struct {
+	Label    string `yaml:"label"`
+	Match    string `yaml:"match"`
+	TestOnly *bool  `yaml:"test_only"`
+	Replaced *bool  `yaml:"replaced"`
+	Age      string `yaml:"age"`
+	Fill     string `yaml:"fill"`
+	Text     string `yaml:"text"`
+	Border   string `yaml:"border"`
+}
+
+var (
+	ageRE = regexp.MustCompile(`^(>=|<=|>|<)\s*([0-9]+)\s*(d|w|m|y)$`)
+
+	ageUnits = map[string]time.Duration{
+		"d": 24 * time.Hour,
+		"w": 7 * 24 * time.Hour,
+		"m": 30 * 24 * time.Hour,
+		"y": 365 * 24 * time.Hour,
+	}
+)
+
+func ParseTheme(log *logger.Logger, path string) (*printer.Theme, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		log.Error("Could not open theme file.", zap.String("path", path), zap.Error(err))
+		return nil, err
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	var raw themeFile
+	decoder := yaml.NewDecoder(f)
+	decoder.KnownFields(true)
+	if err = decoder.Decode(&raw); err != nil {
+		log.Error("Could not parse theme file.", zap.String("path", path), zap.Error(err))
+		return nil, errors.New("invalid theme file")
+	}
+
+	rules := make([]printer.ThemeRule, 0, len(raw.Rules))
+	for idx, r := range raw.Rules {
+		rule, err := parseThemeRule(log, idx+1, r)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return printer.NewTheme(rules), nil
+}
+
+func parseThemeRule(log *logger.Logger, number int, raw themeFileRule) (printer.ThemeRule, error) {
+	rule := printer.ThemeRule{
+		Label:    raw.Label,
+		Pattern:  raw.Match,
+		TestOnly: raw.TestOnly,
+		Replaced: raw.Replaced,
+		Fill:     raw.Fill,
+		Text:     raw.Text,
+		Border:   raw.Border,
+	}
+	if rule.Fill == "" && rule.Text == "" && rule.Border == "" {
+		log.Error("Theme rule does not set any of the 'fill', 'text' or 'border' colours.", zap.Int("rule", number))
+		return rule, errors.New("invalid theme rule")
+	}
+
+	var conditions []string
+	if raw.Match != "" {
+		conditions = append(conditions, raw.Match)
+	}
+	if raw.TestOnly != nil {
+		conditions = append(conditions, negate("test-only", *raw.TestOnly))
+	}
+	if raw.Replaced != nil {
+		conditions = append(conditions, negate("replaced", *raw.Replaced))
+	}
+	if raw.Age != "" {
+		m := ageRE.FindStringSubmatch(strings.ToLower(strings.TrimSpace(raw.Age)))
+		if m == nil {
+			log.Error("Could not parse 'age' of theme rule. Expected one of '>', '>=', '<' or '<=' followed by a number of days (d), weeks (w), months (m) or years (y), such as '>2y'.", zap.Int("rule", number), zap.String("value", raw.Age))
+			return rule, errors.New("invalid theme rule")
+		}
+		count, err := strconv.Atoi(m[2])
+		if err != nil {
+			log.Error("Could not parse 'age' of theme rule.", zap.Int("rule", number), zap.String("value", raw.Age), zap.Error(err))
+			return rule, errors.New("invalid theme rule")
+		}
+
+		rule.Age = &printer.AgeCondition{Age: time.Duration(count) * ageUnits[m[3]]}
+		switch m[1] {
+		case ">":
+			rule.Age.Comparison = depgraph.Greater
+		case ">=":
+			rule.Age.Comparison = depgraph.GreaterOrEqual
+		case "<":
+			rule.Age.Comparison = depgraph.Less
+		default:
+			rule.Age.Comparison = depgraph.LessOrEqual
+		}
+		conditions = append(conditions, "age "+m[1]+m[2]+m[3])
+	}
+
+	if rule.Label == "" {
+		rule.Label = strings.Join(conditions, ", ")
+	}
+	if rule.Label == "" {
+		rule.Label = "all"
+	}
+	return rule, nil
+}
+
+func negate(condition string, value bool) string {
+	if value {
+		return condition
+	}
+	return "not " + condition
+}
@@ -0,0 +1,103 @@
+package parsers
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Helcaraxan/gomod/internal/depgraph"
+	"github.com/Helcaraxan/gomod/internal/printer"
+	"github.com/Helcaraxan/gomod/internal/testutil"
+)
+
+func TestTheme(t *testing.T) {
+	yes, no := true, false
+
+	testcases := map[string]struct {
+		content       string
+		expectedRules []printer.ThemeRule
+		expectedError bool
+	}{
+		"Empty": {
+			content:       "rules: []\n",
+			expectedRules: []printer.ThemeRule{},
+		},
+		"Pattern": {
+			content: `rules:
+  - label: Our modules
+    match: github.com/our/**
+    fill: "#1f77b4"
+    text: white
+`,
+			expectedRules: []printer.ThemeRule{
+				{Label: "Our modules", Pattern: "github.com/our/**", Fill: "#1f77b4", Text: "white"},
+			},
+		},
+		"GeneratedLabels": {
+			content: `rules:
+  - test_only: true
+    replaced: false
+    fill: lightgrey
+  - age: ">= 2y"
+    border: red
+`,
+			expectedRules: []printer.ThemeRule{
+				{Label: "test-only, not replaced", TestOnly: &yes, Replaced: &no, Fill: "lightgrey"},
+				{
+					Label:  "age >=2y",
+					Age:    &printer.AgeCondition{Comparison: depgraph.GreaterOrEqual, Age: 2 * 365 * 24 * time.Hour},
+					Border: "red",
+				},
+			},
+		},
+		"AgeInWeeks": {
+			content: `rules:
+  - age: <6w
+    fill: green
+`,
+			expectedRules: []printer.ThemeRule{
+				{Label: "age <6w", Age: &printer.AgeCondition{Comparison: depgraph.Less, Age: 6 * 7 * 24 * time.Hour}, Fill: "green"},
+			},
+		},
+		"NoColour": {
+			content: `rules:
+  - match: github.com/our/**
+`,
+			expectedError: true,
+		},
+		"InvalidAge": {
+			content: `rules:
+  - age: old
+    fill: red
+`,
+			expectedError: true,
+		},
+		"UnknownField": {
+			content: `rules:
+  - colour: red
+`,
+			expectedError: true,
+		},
+	}
+
+	for name := range testcases {
+		testcase := testcases[name]
+		t.Run(name, func(t *testing.T) {
+			log := testutil.TestLogger(t)
+			path := filepath.Join(t.TempDir(), "theme.yaml")
+			require.NoError(t, ioutil.WriteFile(path, []byte(testcase.content), 0o600))
+
+			theme, err := ParseTheme(log.Log(), path)
+			if testcase.expectedError {
+				assert.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, testcase.expectedRules, theme.Rules)
+			}
+		})
+	}
+}
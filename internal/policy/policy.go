@@ -0,0 +1,154 @@
+package policy
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+
+	"github.com/bmatcuk/doublestar/v3"
+	"go.uber.org/zap"
+	"golang.org/x/mod/modfile"
+
+	"github.com/Helcaraxan/gomod/internal/depgraph"
+	"github.com/Helcaraxan/gomod/internal/graph"
+	"github.com/Helcaraxan/gomod/internal/logger"
+)
+
+// Policy lists the modules that may and may not be part of a dependency graph.
+type Policy struct {
+	// Modules that may not be part of the graph.
+	Banned []Entry
+	// Modules that may be part of the graph. When empty all modules that are not banned are allowed.
+	Allowed []Entry
+}
+
+// Entry selects modules via a glob pattern on their path and, optionally, a range of versions.
+type Entry struct {
+	Pattern  string
+	Versions VersionRange
+	Reason   string
+}
+
+func (e Entry) String() string {
+	if len(e.Versions) == 0 {
+		return e.Pattern
+	}
+	return fmt.Sprintf("%s (%s)", e.Pattern, e.Versions)
+}
+
+func (e Entry) matchesPath(module *depgraph.Module) bool {
+	match, _ := doublestar.Match(e.Pattern, module.Name())
+	return match
+}
+
+func (e Entry) matches(module *depgraph.Module) bool {
+	return e.matchesPath(module) && e.Versions.Contains(module.SelectedVersion())
+}
+
+// FindingKind describes in which way a module breaks a policy.
+type FindingKind string
+
+const (
+	// The module is selected by one of the banned entries of the policy.
+	Banned FindingKind = "banned"
+	// The module is not selected by any of the allowed entries of the policy.
+	Unapproved FindingKind = "unapproved"
+)
+
+// Finding is a module that breaks a policy.
+type Finding struct {
+	Kind    FindingKind `json:"kind"`
+	Module  string      `json:"module"`
+	Version string      `json:"version,omitempty"`
+	// Policy entry that bans the module.
+	Entry  string `json:"entry,omitempty"`
+	Reason string `json:"reason"`
+	// Shortest chain of modules through which the module is introduced, starting at the main module.
+	Chain []string `json:"chain,omitempty"`
+	// Line of the main module's go.mod at which the module is required. Zero if it is not required.
+	Line int `json:"line,omitempty"`
+}
+
+func (f Finding) String() string {
+	if f.Version == "" {
+		return f.Module
+	}
+	return f.Module + "@" + f.Version
+}
+
+// Evaluate returns the modules of the dependency graph that break the policy, ordered by module path.
+// Modules selected by a banned entry are reported as such even if they are also allowed.
+func Evaluate(log *logger.Logger, g *depgraph.DepGraph, p *Policy) []Finding {
+	lines := requireLines(log, filepath.Join(g.Path, "go.mod"))
+	chains := depgraph.NewDependencyChains([]graph.Node{g.Main})
+
+	var findings []Finding
+	for _, node := range g.Graph.GetLevel(int(depgraph.LevelModules)).List() {
+		module := node.(*depgraph.Module)
+		if module == g.Main || module.Name() == depgraph.StdLibModule {
+			continue
+		}
+
+		finding, ok := p.check(module)
+		if !ok {
+			continue
+		}
+		log.Debug("Found module breaking the policy.", zap.String("module", module.Name()), zap.String("kind", string(finding.Kind)))
+		finding.Module = module.Name()
+		finding.Version = module.SelectedVersion()
+		finding.Chain = chains.Chain(module.Name())
+		finding.Line = lines[module.Name()]
+		findings = append(findings, finding)
+	}
+	sort.Slice(findings, func(i int, j int) bool { return findings[i].Module < findings[j].Module })
+	return findings
+}
+
+func (p *Policy) check(module *depgraph.Module) (Finding, bool) {
+	for _, entry := range p.Banned {
+		if entry.matches(module) {
+			reason := entry.Reason
+			if reason == "" {
+				reason = fmt.Sprintf("banned by %s", entry)
+			}
+			return Finding{Kind: Banned, Entry: entry.String(), Reason: reason}, true
+		}
+	}
+	if len(p.Allowed) == 0 {
+		return Finding{}, false
+	}
+
+	reason := "not on the list of allowed modules"
+	for _, entry := range p.Allowed {
+		if entry.matches(module) {
+			return Finding{}, false
+		} else if entry.matchesPath(module) {
+			reason = fmt.Sprintf("version is not allowed by %s", entry)
+		}
+	}
+	return Finding{Kind: Unapproved, Reason: reason}, true
+}
+
+// requireLines maps the modules required by the go.mod file at the given path to the line at which
+// they are required.
+func requireLines(log *logger.Logger, path string) map[string]int {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		log.Debug("Could not read go.mod file, findings will not point to their requirement.", zap.String("path", path), zap.Error(err))
+		return nil
+	}
+	file, err := modfile.ParseLax(path, content, nil)
+	if err != nil {
+		log.Debug("Could not parse go.mod file, findings will not point to their requirement.", zap.String("path", path), zap.Error(err))
+		return nil
+	}
+
+	lines := make(map[string]int, len(file.Require))
+	for _, r := range file.Require {
+		if _, ok := lines[r.Mod.Path]; !ok {
+			lines[r.Mod.Path] = r.Syntax.Start.Line
+		}
+	}
+	return lines
+}
@@ -0,0 +1,136 @@
+package policy
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Helcaraxan/gomod/internal/depgraph"
+	"github.com/Helcaraxan/gomod/internal/testutil"
+)
+
+const testGoMod = `module test
+
+go 1.14
+
+require example.com/a v1.0.0
+
+require (
+	// Pinned for compatibility.
+	example.com/b v1.1.0 // indirect
+	example.com/c v0.1.0
+)
+
+replace example.com/a => example.com/a v1.0.1
+
+exclude (
+	example.com/b v1.0.0
+)
+`
+
+func TestEvaluate(t *testing.T) {
+	cwd, err := os.Getwd()
+	require.NoError(t, err)
+
+	testcases := map[string]struct {
+		policy   *Policy
+		expected []Finding
+	}{
+		"Empty": {
+			policy: &Policy{},
+		},
+		"Banned": {
+			policy: &Policy{Banned: []Entry{{Pattern: "example.com/b", Reason: "use example.com/a instead"}}},
+			expected: []Finding{
+				{Kind: Banned, Module: "example.com/b", Version: "v1.1.0", Entry: "example.com/b", Reason: "use example.com/a instead", Chain: []string{"test", "example.com/b"}, Line: 9},
+			},
+		},
+		"BannedVersions": {
+			policy: &Policy{Banned: []Entry{{Pattern: "example.com/*", Versions: VersionRange{{Comparison: depgraph.Less, Version: "v1.0.0"}}}}},
+			expected: []Finding{
+				{Kind: Banned, Module: "example.com/c", Version: "v0.1.0", Entry: "example.com/* (<v1.0.0)", Reason: "banned by example.com/* (<v1.0.0)", Chain: []string{"test", "example.com/c"}, Line: 10},
+			},
+		},
+		"Unapproved": {
+			policy: &Policy{Allowed: []Entry{
+				{Pattern: "example.com/a"},
+				{Pattern: "example.com/b", Versions: VersionRange{{Comparison: depgraph.GreaterOrEqual, Version: "v1.2.0"}}},
+			}},
+			expected: []Finding{
+				{Kind: Unapproved, Module: "example.com/b", Version: "v1.1.0", Reason: "version is not allowed by example.com/b (>=v1.2.0)", Chain: []string{"test", "example.com/b"}, Line: 9},
+				{Kind: Unapproved, Module: "example.com/c", Version: "v0.1.0", Reason: "not on the list of allowed modules", Chain: []string{"test", "example.com/c"}, Line: 10},
+			},
+		},
+		"BannedBeforeAllowed": {
+			policy: &Policy{
+				Banned:  []Entry{{Pattern: "example.com/a", Reason: "deprecated"}},
+				Allowed: []Entry{{Pattern: "example.com/**"}},
+			},
+			expected: []Finding{
+				{Kind: Banned, Module: "example.com/a", Version: "v1.0.0", Entry: "example.com/a", Reason: "deprecated", Chain: []string{"test", "example.com/a"}, Line: 5},
+			},
+		},
+	}
+
+	for name := range testcases {
+		testcase := testcases[name]
+		t.Run(name, func(t *testing.T) {
+			testDir := testutil.SetupTestModule(t, filepath.Join(cwd, "testdata", "Policy.yaml"), &testutil.GraphTestDefinition{})
+			require.NoError(t, ioutil.WriteFile(filepath.Join(testDir, "go.mod"), []byte(testGoMod), 0o600))
+
+			log := testutil.TestLogger(t)
+			g, err := depgraph.GetGraph(log, testDir, nil)
+			require.NoError(t, err)
+
+			assert.Equal(t, testcase.expected, Evaluate(log.Log(), g, testcase.policy))
+		})
+	}
+}
+
+func TestPrint(t *testing.T) {
+	findings := []Finding{
+		{Kind: Banned, Module: "example.com/b", Version: "v1.1.0", Entry: "example.com/b", Reason: "use example.com/a instead", Chain: []string{"test", "example.com/a", "example.com/b"}, Line: 9},
+		{Kind: Unapproved, Module: "example.com/d", Reason: "not on the list of allowed modules"},
+	}
+
+	t.Run("Text", func(t *testing.T) {
+		var out bytes.Buffer
+		require.NoError(t, Print(&out, findings, FormatText))
+		assert.Equal(t, `BANNED example.com/b@v1.1.0: use example.com/a instead
+  required at go.mod:9
+  test -> example.com/a -> example.com/b
+UNAPPROVED example.com/d: not on the list of allowed modules
+`, out.String())
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		var out bytes.Buffer
+		require.NoError(t, Print(&out, nil, FormatJSON))
+		assert.JSONEq(t, `{"findings": []}`, out.String())
+	})
+
+	t.Run("SARIF", func(t *testing.T) {
+		var out bytes.Buffer
+		require.NoError(t, Print(&out, findings, FormatSARIF))
+
+		var log sarifLog
+		require.NoError(t, json.Unmarshal(out.Bytes(), &log))
+		require.Len(t, log.Runs, 1)
+		results := log.Runs[0].Results
+		require.Len(t, results, 2)
+
+		assert.Equal(t, "banned-module", results[0].RuleID)
+		assert.Equal(t, "Module example.com/b@v1.1.0 is banned: use example.com/a instead. It is introduced via test -> example.com/a -> example.com/b.", results[0].Message.Text)
+		assert.Equal(t, &sarifRegion{StartLine: 9}, results[0].Locations[0].PhysicalLocation.Region)
+
+		assert.Equal(t, "unapproved-module", results[1].RuleID)
+		assert.Equal(t, "go.mod", results[1].Locations[0].PhysicalLocation.ArtifactLocation.URI)
+		assert.Nil(t, results[1].Locations[0].PhysicalLocation.Region)
+	})
+}
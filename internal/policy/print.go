@@ -0,0 +1,185 @@
+package policy
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Format in which findings are printed.
+type Format uint8
+
+const (
+	// Human-readable lines listing each finding with the chain of modules that introduces it.
+	FormatText Format = iota
+	// A JSON object with a 'findings' array.
+	FormatJSON
+	// A SARIF 2.1.0 log that can be consumed by code-scanning tools and CI annotators.
+	FormatSARIF
+)
+
+func (f Format) String() string {
+	return map[Format]string{
+		FormatText:  "text",
+		FormatJSON:  "json",
+		FormatSARIF: "sarif",
+	}[f]
+}
+
+// Print writes the findings in the requested format.
+func Print(w io.Writer, findings []Finding, format Format) error {
+	var err error
+	switch format {
+	case FormatJSON:
+		if findings == nil {
+			findings = []Finding{}
+		}
+		err = printJSON(w, struct {
+			Findings []Finding `json:"findings"`
+		}{Findings: findings})
+	case FormatSARIF:
+		err = printJSON(w, newSARIFLog(findings))
+	default:
+		err = printText(w, findings)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to print policy findings: %v", err)
+	}
+	return nil
+}
+
+func printJSON(w io.Writer, v interface{}) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+func printText(w io.Writer, findings []Finding) error {
+	if len(findings) == 0 {
+		_, err := fmt.Fprintln(w, "No modules breaking the policy.")
+		return err
+	}
+
+	var lines []string
+	for _, finding := range findings {
+		lines = append(lines, fmt.Sprintf("%s %s: %s", strings.ToUpper(string(finding.Kind)), finding, finding.Reason))
+		if finding.Line > 0 {
+			lines = append(lines, fmt.Sprintf("  required at go.mod:%d", finding.Line))
+		}
+		if len(finding.Chain) > 1 {
+			lines = append(lines, "  "+strings.Join(finding.Chain, " -> "))
+		}
+	}
+	_, err := fmt.Fprintln(w, strings.Join(lines, "\n"))
+	return err
+}
+
+// The subset of the SARIF 2.1.0 format that is needed to report policy findings. See
+// https://docs.oasis-open.org/sarif/sarif/v2.1.0/sarif-v2.1.0.html for the full specification.
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifResult struct {
+	RuleID     string                 `json:"ruleId"`
+	Level      string                 `json:"level"`
+	Message    sarifMessage           `json:"message"`
+	Locations  []sarifLocation        `json:"locations"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+var sarifRules = map[FindingKind]sarifRule{
+	Banned:     {ID: "banned-module", ShortDescription: sarifMessage{Text: "Module is banned by the dependency policy."}},
+	Unapproved: {ID: "unapproved-module", ShortDescription: sarifMessage{Text: "Module is not approved by the dependency policy."}},
+}
+
+// newSARIFLog reports each finding as an error located at the go.mod line requiring the module, or at
+// the go.mod file itself for modules that are only required indirectly.
+func newSARIFLog(findings []Finding) *sarifLog {
+	results := make([]sarifResult, 0, len(findings))
+	for _, finding := range findings {
+		text := fmt.Sprintf("Module %s is %s: %s.", finding, finding.Kind, finding.Reason)
+		if len(finding.Chain) > 1 {
+			text += " It is introduced via " + strings.Join(finding.Chain, " -> ") + "."
+		}
+
+		location := sarifLocation{PhysicalLocation: sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: "go.mod"}}}
+		if finding.Line > 0 {
+			location.PhysicalLocation.Region = &sarifRegion{StartLine: finding.Line}
+		}
+
+		properties := map[string]interface{}{"module": finding.Module}
+		if finding.Version != "" {
+			properties["version"] = finding.Version
+		}
+		if len(finding.Chain) > 0 {
+			properties["chain"] = finding.Chain
+		}
+
+		results = append(results, sarifResult{
+			RuleID:     sarifRules[finding.Kind].ID,
+			Level:      "error",
+			Message:    sarifMessage{Text: text},
+			Locations:  []sarifLocation{location},
+			Properties: properties,
+		})
+	}
+
+	return &sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs: []sarifRun{{
+			Tool: sarifTool{Driver: sarifDriver{
+				Name:           "gomod",
+				InformationURI: "https://github.com/Helcaraxan/gomod",
+				Rules:          []sarifRule{sarifRules[Banned], sarifRules[Unapproved]},
+			}},
+			Results: results,
+		}},
+	}
+}
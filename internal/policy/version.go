@@ -0,0 +1,56 @@
+package policy
+
+import (
+	"strings"
+
+	"golang.org/x/mod/semver"
+
+	"github.com/Helcaraxan/gomod/internal/depgraph"
+)
+
+// VersionConstraint compares the version of a module with a fixed version.
+type VersionConstraint struct {
+	Comparison depgraph.Comparison
+	Version    string
+}
+
+func (c VersionConstraint) String() string {
+	return c.Comparison.String() + c.Version
+}
+
+// Matches returns whether the given version satisfies the constraint. Versions that are not valid
+// semantic versions, such as those of modules replaced by a local directory, never do.
+func (c VersionConstraint) Matches(version string) bool {
+	if !semver.IsValid(version) {
+		return false
+	}
+	return c.Comparison.Compare(semver.Compare(version, c.Version), 0)
+}
+
+// VersionRange is a set of constraints that a version needs to satisfy all at once. An empty range
+// contains all versions.
+type VersionRange []VersionConstraint
+
+func (r VersionRange) String() string {
+	constraints := make([]string, 0, len(r))
+	for _, c := range r {
+		constraints = append(constraints, c.String())
+	}
+	return strings.Join(constraints, ", ")
+}
+
+// Contains returns whether the given version satisfies all the constraints of the range.
+func (r VersionRange) Contains(version string) bool {
+	for _, c := range r {
+		if !c.Matches(version) {
+			return false
+		}
+	}
+	return true
+}
+
+// IsValidVersion indicates whether the given string is a complete semantic version such as 'v1.2.3'
+// or 'v1.2.3-rc.1'. Shorthands like 'v1' or 'v1.2' are not accepted.
+func IsValidVersion(version string) bool {
+	return semver.IsValid(version) && semver.Canonical(version) == strings.TrimSuffix(version, semver.Build(version))
+}
@@ -0,0 +1,82 @@
+package policy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Helcaraxan/gomod/internal/depgraph"
+)
+
+func TestIsValidVersion(t *testing.T) {
+	for version, expected := range map[string]bool{
+		"v1.2.3":                             true,
+		"v1.0.0-rc.1":                        true,
+		"v0.0.0-20190605123033-f99c8df09eb5": true,
+		"v2.1.0+incompatible":                true,
+		"v1":                                 false,
+		"v1.2":                               false,
+		"1.2.3":                              false,
+		"../local":                           false,
+	} {
+		assert.Equal(t, expected, IsValidVersion(version), version)
+	}
+}
+
+func TestVersionConstraint(t *testing.T) {
+	testcases := map[string]struct {
+		a, b     string
+		expected int
+	}{
+		"Equal":            {a: "v1.2.3", b: "v1.2.3", expected: 0},
+		"Major":            {a: "v2.0.0", b: "v1.9.9", expected: 1},
+		"Minor":            {a: "v1.2.0", b: "v1.10.0", expected: -1},
+		"Patch":            {a: "v1.0.10", b: "v1.0.9", expected: 1},
+		"Prerelease":       {a: "v1.0.0-rc.1", b: "v1.0.0", expected: -1},
+		"PrereleaseNumber": {a: "v1.0.0-rc.2", b: "v1.0.0-rc.10", expected: -1},
+		"PrereleaseAlpha":  {a: "v1.0.0-beta", b: "v1.0.0-alpha.1", expected: 1},
+		"PrereleaseLength": {a: "v1.0.0-alpha", b: "v1.0.0-alpha.1", expected: -1},
+		"Pseudo":           {a: "v0.0.0-20190605123033-f99c8df09eb5", b: "v0.0.0-20200101000000-000000000000", expected: -1},
+		"Incompatible":     {a: "v2.1.0+incompatible", b: "v2.1.0", expected: 0},
+	}
+
+	for name := range testcases {
+		testcase := testcases[name]
+		t.Run(name, func(t *testing.T) {
+			for _, pair := range []struct {
+				version, constraint string
+				expected            int
+			}{
+				{version: testcase.a, constraint: testcase.b, expected: testcase.expected},
+				{version: testcase.b, constraint: testcase.a, expected: -testcase.expected},
+			} {
+				for comparison, matches := range map[depgraph.Comparison]bool{
+					depgraph.Less:           pair.expected < 0,
+					depgraph.LessOrEqual:    pair.expected <= 0,
+					depgraph.Equal:          pair.expected == 0,
+					depgraph.GreaterOrEqual: pair.expected >= 0,
+					depgraph.Greater:        pair.expected > 0,
+				} {
+					c := VersionConstraint{Comparison: comparison, Version: pair.constraint}
+					assert.Equal(t, matches, c.Matches(pair.version), "%s %s", pair.version, c)
+				}
+			}
+		})
+	}
+
+	assert.False(t, VersionConstraint{Comparison: depgraph.LessOrEqual, Version: "v1.0.0"}.Matches("../local"))
+}
+
+func TestVersionRange(t *testing.T) {
+	r := VersionRange{
+		{Comparison: depgraph.GreaterOrEqual, Version: "v1.2.0"},
+		{Comparison: depgraph.Less, Version: "v2.0.0"},
+	}
+	assert.Equal(t, ">=v1.2.0, <v2.0.0", r.String())
+	assert.True(t, r.Contains("v1.2.0"))
+	assert.True(t, r.Contains("v1.9.0"))
+	assert.False(t, r.Contains("v1.2.0-rc.1"))
+	assert.False(t, r.Contains("v2.0.0"))
+	assert.False(t, r.Contains(""))
+	assert.True(t, VersionRange{}.Contains(""))
+}
@@ -11,14 +11,13 @@ import (
 
 func computeGraphClusters(g *graph.HierarchicalDigraph, config *PrintConfig) *graphClusters {
 	graphClusters := &graphClusters{
-		graph:           g,
-		level:           config.Granularity,
+		levelNodes:      g.GetLevel(int(config.Granularity)),
 		clusterMap:      map[string]*graphCluster{},
 		cachedDepthMaps: map[string]map[string]int{},
 	}
 
 	hashToCluster := map[string]*graphCluster{}
-	for _, node := range g.GetLevel(int(config.Granularity)).List() {
+	for _, node := range graphClusters.levelNodes.List() {
 		clusterHash := computeClusterHash(config, node)
 		cluster := hashToCluster[clusterHash]
 		if cluster == nil {
@@ -47,9 +46,49 @@ func computeGraphClusters(g *graph.HierarchicalDigraph, config *PrintConfig) *gr
 	sort.Slice(graphClusters.clusterList, func(i int, j int) bool {
 		return graphClusters.clusterList[i].hash < graphClusters.clusterList[j].hash
 	})
+	// Cluster IDs are only unique within a single print and follow the order of the clusters so
+	// that they do not depend on which graphs have been printed before.
+	for idx, cluster := range graphClusters.clusterList {
+		cluster.id = idx
+	}
+
+	if config.Style.clusterParents() && config.Granularity == LevelPackages {
+		graphClusters.parentGroups = groupClustersByParent(graphClusters.clusterList)
+	}
 	return graphClusters
 }
 
+// parentGroup contains the clusters whose members all share the same parent node.
+type parentGroup struct {
+	parent   graph.Node
+	clusters []*graphCluster
+}
+
+func (p *parentGroup) name() string {
+	return "cluster_parent_" + nodeNameToHash(p.parent.Name())
+}
+
+// groupClustersByParent groups the given clusters by the parent of their members. The order of the
+// groups, and that of the clusters within each group, follows the order of the given list.
+func groupClustersByParent(clusters []*graphCluster) []*parentGroup {
+	var groups []*parentGroup
+	byParent := map[string]*parentGroup{}
+	for _, cluster := range clusters {
+		if len(cluster.members) == 0 {
+			continue
+		}
+		parent := cluster.members[0].Parent()
+		group, ok := byParent[parent.Hash()]
+		if !ok {
+			group = &parentGroup{parent: parent}
+			byParent[parent.Hash()] = group
+			groups = append(groups, group)
+		}
+		group.clusters = append(group.clusters, cluster)
+	}
+	return groups
+}
+
 func computeClusterHash(config *PrintConfig, node graph.Node) string {
 	var hashElements []string
 	for _, pred := range node.Predecessors().List() {
@@ -59,18 +98,25 @@ func computeClusterHash(config *PrintConfig, node graph.Node) string {
 	hash := strings.Join(hashElements, "_")
 
 	// Depending on the configuration we need to generate more or less unique cluster names.
-	if config.Style == nil || config.Style.Cluster == Off || (config.Style.Cluster == Shared && node.Predecessors().Len() > 1) {
+	if config.Style == nil || config.Style.Cluster <= Parent || (config.Style.Cluster == Shared && node.Predecessors().Len() > 1) {
 		hash = node.Name() + "_from_" + hash
 	}
+	// Clusters should not span multiple parents if nodes are also grouped by parent.
+	if config.Style.clusterParents() && node.Parent() != nil {
+		hash = nodeNameToHash(node.Parent().Name()) + "_in_" + hash
+	}
 	return hash
 }
 
 type graphClusters struct {
-	graph *graph.HierarchicalDigraph
-	level Level
+	// Nodes of the graph at the printed level. These are retrieved once as doing so requires
+	// iterating over the entire graph.
+	levelNodes graph.NodeRefs
 
 	clusterMap  map[string]*graphCluster
 	clusterList []*graphCluster
+	// Groups of clusters sharing the same parent. Only set when nodes are clustered by parent.
+	parentGroups []*parentGroup
 
 	cachedDepthMaps map[string]map[string]int
 }
@@ -81,36 +127,36 @@ func (c *graphClusters) clusterDepthMap(nodeHash string) map[string]int {
 	}
 
 	depthMap := map[string]int{}
-	levelNodes := c.graph.GetLevel(int(c.level))
-
-	startNode, _ := levelNodes.Get(nodeHash)
+	startNode, _ := c.levelNodes.Get(nodeHash)
 	workStack := []graph.Node{startNode}
 	workMap := map[string]int{nodeHash: 0}
 	pathLength := 0
 	for len(workStack) > 0 {
 		pathLength++
 		curr := workStack[len(workStack)-1]
-		if counter, ok := workMap[curr.Hash()]; ok && counter > 0 { // Reached leaf of the DFS or cycle detected.
+		currHash := curr.Hash()
+		if counter, ok := workMap[currHash]; ok && counter > 0 { // Reached leaf of the DFS or cycle detected.
 			workStack = workStack[:len(workStack)-1]
 			pathLength--
 			if counter == pathLength-1 { // Reached leaf of the DFS.
-				delete(workMap, curr.Hash())
+				delete(workMap, currHash)
 			}
 			continue
 		}
-		workMap[curr.Hash()] = pathLength
+		workMap[currHash] = pathLength
 
-		currentDepth := depthMap[curr.Hash()]
-		baseEdgeLength := c.clusterMap[curr.Hash()].getHeight()
+		currentDepth := depthMap[currHash]
+		cluster := c.clusterMap[currHash]
+		edgeLength := cluster.getHeight() + cluster.getDepCount()/20 // Give bonus space for larger numbers of edges.
 		for _, pred := range curr.Predecessors().List() {
-			predNode, _ := levelNodes.Get(pred.Hash())
-			edgeLength := baseEdgeLength + c.clusterMap[curr.Hash()].getDepCount()/20 // Give bonus space for larger numbers of edges.
-			if depthMap[pred.Hash()] >= currentDepth+edgeLength {
+			predHash := pred.Hash()
+			if depthMap[predHash] >= currentDepth+edgeLength {
 				continue
 			}
-			depthMap[pred.Hash()] = currentDepth + edgeLength
-			if _, ok := workMap[pred.Hash()]; !ok { // Only allow one instance of a node in the queue.
-				workMap[pred.Hash()] = 0
+			depthMap[predHash] = currentDepth + edgeLength
+			if _, ok := workMap[predHash]; !ok { // Only allow one instance of a node in the queue.
+				predNode, _ := c.levelNodes.Get(predHash)
+				workMap[predHash] = 0
 				workStack = append(workStack, predNode)
 			}
 		}
@@ -129,12 +175,8 @@ type graphCluster struct {
 	cachedWidth    int
 }
 
-var clusterIDCounter int
-
 func newGraphCluster(hash string) *graphCluster {
-	clusterIDCounter++
 	return &graphCluster{
-		id:             clusterIDCounter,
 		hash:           hash,
 		cachedDepCount: -1,
 		cachedWidth:    -1,
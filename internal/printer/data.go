@@ -0,0 +1,162 @@
+package printer
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/Helcaraxan/gomod/internal/depgraph"
+	"github.com/Helcaraxan/gomod/internal/graph"
+)
+
+type dataType uint8
+
+const (
+	dataString dataType = iota
+	dataBool
+	dataInt
+)
+
+type dataKey struct {
+	name string
+	kind dataType
+}
+
+// The properties, and their types, that are exported for nodes and edges. The order determines the
+// order in which they are declared and printed.
+var (
+	nodeDataKeys = []dataKey{
+		{name: "path", kind: dataString},
+		{name: "module", kind: dataString},
+		{name: "version", kind: dataString},
+		{name: "replacement", kind: dataString},
+		{name: "test_only", kind: dataBool},
+		{name: "indirect", kind: dataBool},
+		{name: "package_count", kind: dataInt},
+		{name: "timestamp", kind: dataString},
+		{name: "member_count", kind: dataInt},
+	}
+	edgeDataKeys = []dataKey{
+		{name: "indirect", kind: dataBool},
+		{name: "test_only", kind: dataBool},
+		{name: "version_constraint", kind: dataString},
+		{name: "weight", kind: dataInt},
+		{name: "edge_count", kind: dataInt},
+	}
+	// Property of both nodes and edges that is only exported when highlighting is in effect.
+	highlightDataKey = dataKey{name: "highlighted", kind: dataBool}
+	// Properties of nodes holding the code footprint that their size is based on. Those that are not
+	// part of the default node properties are only exported when the corresponding sizing is in
+	// effect.
+	footprintDataKeys = map[NodeScale]dataKey{
+		ScaleByFiles:    {name: "file_count", kind: dataInt},
+		ScaleByLines:    {name: "line_count", kind: dataInt},
+		ScaleByPackages: {name: "package_count", kind: dataInt},
+	}
+)
+
+// graphDataKeys returns the node and edge properties that are exported for the given configuration.
+func graphDataKeys(config *PrintConfig) (nodeKeys []dataKey, edgeKeys []dataKey) {
+	nodeKeys, edgeKeys = nodeDataKeys, edgeDataKeys
+	if config.sizes != nil && config.sizes.metric != ScaleByPackages {
+		nodeKeys = append(append([]dataKey{}, nodeKeys...), footprintDataKeys[config.sizes.metric])
+	}
+	if config.Highlight != nil {
+		nodeKeys = append(append([]dataKey{}, nodeKeys...), highlightDataKey)
+		edgeKeys = append(append([]dataKey{}, edgeKeys...), highlightDataKey)
+	}
+	return nodeKeys, edgeKeys
+}
+
+type dataValue struct {
+	key   dataKey
+	raw   interface{}
+	value string
+}
+
+// collectData returns the known values for the given keys in their declaration order.
+func collectData(data map[string]interface{}, keys []dataKey) []dataValue {
+	var values []dataValue
+	for _, key := range keys {
+		v, ok := data[key.name]
+		if !ok {
+			continue
+		}
+
+		var value string
+		switch tv := v.(type) {
+		case bool:
+			value = strconv.FormatBool(tv)
+		case int:
+			value = strconv.Itoa(tv)
+		default:
+			value = fmt.Sprint(tv)
+		}
+		values = append(values, dataValue{key: key, raw: v, value: value})
+	}
+	return values
+}
+
+type dataEdge struct {
+	id     string
+	source string
+	target string
+	data   []dataValue
+}
+
+type dataNode struct {
+	id    string
+	label string
+	node  graph.Node
+	data  []dataValue
+}
+
+// collectGraphData extracts all nodes and edges at the configured granularity, together with the
+// values of their properties. Unlike the visual formats no clustering is applied as the consuming
+// tools perform their own layout. When highlighting is in effect each node and edge carries a
+// 'highlighted' property.
+func collectGraphData(g *graph.HierarchicalDigraph, config *PrintConfig) ([]dataNode, []dataEdge) {
+	list := g.GetLevel(int(config.Granularity)).List()
+	nodeKeys, edgeKeys := graphDataKeys(config)
+
+	ids := map[string]string{}
+	for idx, node := range list {
+		ids[node.Hash()] = "n" + strconv.Itoa(idx)
+	}
+
+	var nodes []dataNode
+	var edges []dataEdge
+	for _, node := range list {
+		n := dataNode{id: ids[node.Hash()], label: node.Name(), node: node}
+		a, ok := node.(depgraph.DataAnnotated)
+		if ok {
+			data := a.NodeData()
+			if config.Highlight != nil {
+				data["highlighted"] = config.nodeHighlight(node) == depgraph.Highlighted
+			}
+			if config.sizes != nil {
+				if value, ok := config.sizes.values[node.Hash()]; ok {
+					data[footprintDataKeys[config.sizes.metric].name] = value
+				}
+			}
+			n.data = collectData(data, nodeKeys)
+		}
+		nodes = append(nodes, n)
+
+		for _, dep := range node.Successors().List() {
+			e := dataEdge{
+				id:     "e" + strconv.Itoa(len(edges)),
+				source: ids[node.Hash()],
+				target: ids[dep.Hash()],
+			}
+			if ok {
+				data := a.EdgeData(dep)
+				if config.Highlight != nil {
+					data["highlighted"] = config.edgeHighlight(node, dep) == depgraph.Highlighted
+				}
+				e.data = collectData(data, edgeKeys)
+			}
+			edges = append(edges, e)
+		}
+	}
+	return nodes, edges
+}
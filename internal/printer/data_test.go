@@ -0,0 +1,399 @@
+package printer
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Helcaraxan/gomod/internal/depgraph"
+	"github.com/Helcaraxan/gomod/internal/graph"
+	"github.com/Helcaraxan/gomod/internal/logger"
+	"github.com/Helcaraxan/gomod/internal/modules"
+	"github.com/Helcaraxan/gomod/internal/testutil"
+)
+
+func dataTestGraph(t *testing.T) *graph.HierarchicalDigraph {
+	timestamp := time.Date(2020, 6, 15, 12, 0, 0, 0, time.UTC)
+
+	g := graph.NewHierarchicalDigraph(testutil.TestLogger(t).Domain(logger.GraphDomain))
+	main := depgraph.NewModule(&modules.ModuleInfo{Main: true, Path: "example.com/main"})
+	dep := depgraph.NewModule(&modules.ModuleInfo{
+		Path:     "example.com/dep",
+		Indirect: true,
+		Version:  "v1.0.0",
+		Replace:  &modules.ModuleInfo{Path: "example.com/fork", Version: "v1.1.0", Time: &timestamp},
+	})
+	main.Indirects[dep.Name()] = true
+	main.VersionConstraints[dep.Hash()] = depgraph.VersionConstraint{Source: "v0.9.0", Target: "v1.0.0"}
+
+	mainPkg := depgraph.NewPackage(&modules.PackageInfo{ImportPath: "example.com/main/cmd"}, main)
+	depPkg := depgraph.NewPackage(&modules.PackageInfo{ImportPath: "example.com/dep/lib"}, dep)
+
+	for _, n := range []graph.Node{main, dep, mainPkg, depPkg} {
+		require.NoError(t, g.AddNode(n))
+	}
+	require.NoError(t, g.AddEdge(main, dep)) // The module requirement.
+	require.NoError(t, g.AddEdge(mainPkg, depPkg))
+	return g
+}
+
+func TestPrintGraphData(t *testing.T) {
+	testcases := map[string]struct {
+		format      Format
+		granularity Level
+		expected    string
+	}{
+		"GraphMLModules": {
+			format:      FormatGraphML,
+			granularity: LevelModules,
+			expected: `<?xml version="1.0" encoding="UTF-8"?>
+<graphml xmlns="http://graphml.graphdrawing.org/xmlns" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xsi:schemaLocation="http://graphml.graphdrawing.org/xmlns http://graphml.graphdrawing.org/xmlns/1.0/graphml.xsd">
+  <key id="node_path" for="node" attr.name="path" attr.type="string"></key>
+  <key id="node_module" for="node" attr.name="module" attr.type="string"></key>
+  <key id="node_version" for="node" attr.name="version" attr.type="string"></key>
+  <key id="node_replacement" for="node" attr.name="replacement" attr.type="string"></key>
+  <key id="node_test_only" for="node" attr.name="test_only" attr.type="boolean"></key>
+  <key id="node_indirect" for="node" attr.name="indirect" attr.type="boolean"></key>
+  <key id="node_package_count" for="node" attr.name="package_count" attr.type="int"></key>
+  <key id="node_timestamp" for="node" attr.name="timestamp" attr.type="string"></key>
+  <key id="node_member_count" for="node" attr.name="member_count" attr.type="int"></key>
+  <key id="edge_indirect" for="edge" attr.name="indirect" attr.type="boolean"></key>
+  <key id="edge_test_only" for="edge" attr.name="test_only" attr.type="boolean"></key>
+  <key id="edge_version_constraint" for="edge" attr.name="version_constraint" attr.type="string"></key>
+  <key id="edge_weight" for="edge" attr.name="weight" attr.type="int"></key>
+  <key id="edge_edge_count" for="edge" attr.name="edge_count" attr.type="int"></key>
+  <graph id="G" edgedefault="directed">
+    <node id="n0">
+      <data key="node_path">example.com/dep</data>
+      <data key="node_version">v1.1.0</data>
+      <data key="node_replacement">example.com/fork</data>
+      <data key="node_test_only">true</data>
+      <data key="node_indirect">true</data>
+      <data key="node_package_count">1</data>
+      <data key="node_timestamp">2020-06-15T12:00:00Z</data>
+    </node>
+    <node id="n1">
+      <data key="node_path">example.com/main</data>
+      <data key="node_test_only">true</data>
+      <data key="node_indirect">false</data>
+      <data key="node_package_count">1</data>
+    </node>
+    <edge id="e0" source="n1" target="n0">
+      <data key="edge_indirect">true</data>
+      <data key="edge_test_only">true</data>
+      <data key="edge_version_constraint">v1.0.0</data>
+      <data key="edge_weight">1</data>
+    </edge>
+  </graph>
+</graphml>
+`,
+		},
+		"GEXFPackages": {
+			format:      FormatGEXF,
+			granularity: LevelPackages,
+			expected: `<?xml version="1.0" encoding="UTF-8"?>
+<gexf xmlns="http://gexf.net/1.2" version="1.2">
+  <meta>
+    <creator>gomod</creator>
+  </meta>
+  <graph mode="static" defaultedgetype="directed">
+    <attributes class="node">
+      <attribute id="0" title="path" type="string"></attribute>
+      <attribute id="1" title="module" type="string"></attribute>
+      <attribute id="2" title="version" type="string"></attribute>
+      <attribute id="3" title="replacement" type="string"></attribute>
+      <attribute id="4" title="test_only" type="boolean"></attribute>
+      <attribute id="5" title="indirect" type="boolean"></attribute>
+      <attribute id="6" title="package_count" type="integer"></attribute>
+      <attribute id="7" title="timestamp" type="string"></attribute>
+      <attribute id="8" title="member_count" type="integer"></attribute>
+    </attributes>
+    <attributes class="edge">
+      <attribute id="0" title="indirect" type="boolean"></attribute>
+      <attribute id="1" title="test_only" type="boolean"></attribute>
+      <attribute id="2" title="version_constraint" type="string"></attribute>
+      <attribute id="3" title="weight" type="integer"></attribute>
+      <attribute id="4" title="edge_count" type="integer"></attribute>
+    </attributes>
+    <nodes>
+      <node id="n0" label="example.com/dep/lib">
+        <attvalues>
+          <attvalue for="0" value="example.com/dep/lib"></attvalue>
+          <attvalue for="1" value="example.com/dep"></attvalue>
+          <attvalue for="2" value="v1.1.0"></attvalue>
+          <attvalue for="3" value="example.com/fork"></attvalue>
+          <attvalue for="4" value="true"></attvalue>
+          <attvalue for="5" value="true"></attvalue>
+          <attvalue for="7" value="2020-06-15T12:00:00Z"></attvalue>
+        </attvalues>
+      </node>
+      <node id="n1" label="example.com/main/cmd">
+        <attvalues>
+          <attvalue for="0" value="example.com/main/cmd"></attvalue>
+          <attvalue for="1" value="example.com/main"></attvalue>
+          <attvalue for="4" value="true"></attvalue>
+          <attvalue for="5" value="false"></attvalue>
+        </attvalues>
+      </node>
+    </nodes>
+    <edges>
+      <edge id="e0" source="n1" target="n0">
+        <attvalues>
+          <attvalue for="1" value="true"></attvalue>
+        </attvalues>
+      </edge>
+    </edges>
+  </graph>
+</gexf>
+`,
+		},
+		"JSONModules": {
+			format:      FormatJSON,
+			granularity: LevelModules,
+			expected: `{
+  "nodes": [
+    {
+      "id": "n0",
+      "name": "example.com/dep",
+      "fill_colour": "#b9ccff",
+      "text_colour": "#000000",
+      "data": {
+        "indirect": true,
+        "package_count": 1,
+        "path": "example.com/dep",
+        "replacement": "example.com/fork",
+        "test_only": true,
+        "timestamp": "2020-06-15T12:00:00Z",
+        "version": "v1.1.0"
+      }
+    },
+    {
+      "id": "n1",
+      "name": "example.com/main",
+      "fill_colour": "#ffeca0",
+      "text_colour": "#000000",
+      "data": {
+        "indirect": false,
+        "package_count": 1,
+        "path": "example.com/main",
+        "test_only": true
+      }
+    }
+  ],
+  "edges": [
+    {
+      "source": "n1",
+      "target": "n0",
+      "data": {
+        "indirect": true,
+        "test_only": true,
+        "version_constraint": "v1.0.0",
+        "weight": 1
+      }
+    }
+  ]
+}
+`,
+		},
+	}
+
+	for name := range testcases {
+		testcase := testcases[name]
+		t.Run(name, func(t *testing.T) {
+			outputPath := filepath.Join(t.TempDir(), "graph")
+			require.NoError(t, Print(dataTestGraph(t), &PrintConfig{
+				Log:         testutil.TestLogger(t).Domain(logger.PrinterDomain),
+				Granularity: testcase.granularity,
+				Format:      testcase.format,
+				OutputPath:  outputPath,
+			}))
+
+			actual, err := ioutil.ReadFile(outputPath)
+			require.NoError(t, err)
+			assert.Equal(t, testcase.expected, string(actual))
+		})
+	}
+}
+
+func TestPrintHTML(t *testing.T) {
+	outputPath := filepath.Join(t.TempDir(), "graph.html")
+	require.NoError(t, Print(dataTestGraph(t), &PrintConfig{
+		Log:         testutil.TestLogger(t).Domain(logger.PrinterDomain),
+		Granularity: LevelModules,
+		Format:      FormatHTML,
+		OutputPath:  outputPath,
+	}))
+
+	actual, err := ioutil.ReadFile(outputPath)
+	require.NoError(t, err)
+	page := string(actual)
+	assert.Contains(t, page, `var graph = {"nodes":[{"id":"n0","name":"example.com/dep",`)
+	assert.Contains(t, page, `"edges":[{"source":"n1","target":"n0","data":{"indirect":true,"test_only":true,"version_constraint":"v1.0.0","weight":1}}]}`)
+	assert.NotContains(t, page, "<script src", "The viewer should not depend on any external resources.")
+	assert.NotContains(t, page, "<link")
+}
+
+func TestPrintTables(t *testing.T) {
+	testcases := map[string]struct {
+		format        Format
+		granularity   Level
+		columns       []string
+		expectedNodes string
+		expectedEdges string
+		expectedError bool
+	}{
+		"CSVModules": {
+			format:      FormatCSV,
+			granularity: LevelModules,
+			expectedNodes: `name,level,module,version,replace,test,indirect
+example.com/dep,module,example.com/dep,v1.1.0,example.com/fork,true,true
+example.com/main,module,example.com/main,,,true,false
+`,
+			expectedEdges: `source,target,requested_version,weight,indirect,test
+example.com/main,example.com/dep,v1.0.0,1,true,true
+`,
+		},
+		"TSVPackages": {
+			format:      FormatTSV,
+			granularity: LevelPackages,
+			expectedNodes: "name\tlevel\tmodule\tversion\treplace\ttest\tindirect\n" +
+				"example.com/dep/lib\tpackage\texample.com/dep\tv1.1.0\texample.com/fork\ttrue\ttrue\n" +
+				"example.com/main/cmd\tpackage\texample.com/main\t\t\ttrue\tfalse\n",
+			expectedEdges: "source\ttarget\trequested_version\tweight\tindirect\ttest\n" +
+				"example.com/main/cmd\texample.com/dep/lib\t\t\t\ttrue\n",
+		},
+		"SelectedColumns": {
+			format:      FormatCSV,
+			granularity: LevelModules,
+			columns:     []string{"version", "name", "target"},
+			expectedNodes: `name,version
+example.com/dep,v1.1.0
+example.com/main,
+`,
+			expectedEdges: `target
+example.com/dep
+`,
+		},
+		"NodeColumnsOnly": {
+			format:      FormatCSV,
+			granularity: LevelModules,
+			columns:     []string{"name"},
+			expectedNodes: `name
+example.com/dep
+example.com/main
+`,
+		},
+		"UnknownColumn": {
+			format:        FormatCSV,
+			granularity:   LevelModules,
+			columns:       []string{"name", "colour"},
+			expectedError: true,
+		},
+	}
+
+	for name := range testcases {
+		testcase := testcases[name]
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			err := Print(dataTestGraph(t), &PrintConfig{
+				Log:         testutil.TestLogger(t).Domain(logger.PrinterDomain),
+				Granularity: testcase.granularity,
+				Format:      testcase.format,
+				Columns:     testcase.columns,
+				OutputPath:  filepath.Join(dir, "deps.txt"),
+			})
+			if testcase.expectedError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			for file, expected := range map[string]string{
+				"deps.nodes.txt": testcase.expectedNodes,
+				"deps.edges.txt": testcase.expectedEdges,
+			} {
+				actual, err := ioutil.ReadFile(filepath.Join(dir, file))
+				if expected == "" {
+					assert.Error(t, err, "Table %q should not have been written.", file)
+					continue
+				}
+				require.NoError(t, err)
+				assert.Equal(t, expected, string(actual))
+			}
+		})
+	}
+}
+
+func TestPrintHighlight(t *testing.T) {
+	testcases := map[string]struct {
+		format    Format
+		highlight []string
+		expected  string
+	}{
+		"DOT": {
+			format:    FormatDOT,
+			highlight: []string{"module example.com/dep"},
+			expected: `strict digraph {
+  node [shape=box,style="rounded,filled"]
+  start=0
+  splines=ortho
+  "example.com/dep" [fontcolor="0.000 0.000 0.000",fillcolor="0.620 0.276 1.000",penwidth=3,color="0.000 1.000 0.800"]
+  "example.com/main" [fontcolor="0.000 0.000 0.600",fillcolor="0.133 0.112 1.000",color="0.000 0.000 0.750"]
+  "example.com/main" -> "example.com/dep" [style=dashed,color=lightgrey]
+}
+`,
+		},
+		"Mermaid": {
+			format:    FormatMermaid,
+			highlight: []string{"module example.com/dep", "module example.com/main"},
+			expected: `flowchart TB
+  n0("example.com/dep")
+  n1("example.com/main")
+  n1 -.-> n0
+  classDef s0 fill:#b9ccff,color:#000000,stroke:#cc0000,stroke-width:3px
+  class n0 s0
+  classDef s1 fill:#ffeca0,color:#000000,stroke:#cc0000,stroke-width:3px
+  class n1 s1
+  linkStyle 0 stroke:red,stroke-width:2px
+`,
+		},
+		"CSV": {
+			format:    FormatCSV,
+			highlight: []string{"module example.com/dep"},
+			expected: `name,level,module,version,replace,test,indirect,highlighted
+example.com/dep,module,example.com/dep,v1.1.0,example.com/fork,true,true,true
+example.com/main,module,example.com/main,,,true,false,false
+`,
+		},
+	}
+
+	for name := range testcases {
+		testcase := testcases[name]
+		t.Run(name, func(t *testing.T) {
+			highlight := map[string]bool{}
+			for _, hash := range testcase.highlight {
+				highlight[hash] = true
+			}
+
+			outputPath := filepath.Join(t.TempDir(), "graph")
+			require.NoError(t, Print(dataTestGraph(t), &PrintConfig{
+				Log:         testutil.TestLogger(t).Domain(logger.PrinterDomain),
+				Granularity: LevelModules,
+				Format:      testcase.format,
+				Highlight:   highlight,
+				OutputPath:  outputPath,
+			}))
+
+			if testcase.format == FormatCSV {
+				outputPath += ".nodes"
+			}
+			actual, err := ioutil.ReadFile(outputPath)
+			require.NoError(t, err)
+			assert.Equal(t, testcase.expected, string(actual))
+		})
+	}
+}
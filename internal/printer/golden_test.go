@@ -0,0 +1,150 @@
+package printer
+
+import (
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Helcaraxan/gomod/internal/depgraph"
+	"github.com/Helcaraxan/gomod/internal/logger"
+	"github.com/Helcaraxan/gomod/internal/testutil"
+)
+
+var update = flag.Bool("update", false, "Instead of testing the printed output, use it to refresh the golden files.")
+
+var goldenExtensions = map[Format]string{
+	FormatDOT:     ".dot",
+	FormatMermaid: ".mmd",
+	FormatJSON:    ".json",
+}
+
+// TestPrintGolden prints graphs built from the fixtures in 'testdata' and compares the output with
+// the golden files in 'testdata/golden'. Run with '-update' to regenerate the golden files after an
+// intended change to the printed output.
+func TestPrintGolden(t *testing.T) {
+	cwd, err := os.Getwd()
+	require.NoError(t, err)
+
+	testcases := map[string]struct {
+		fixture     string
+		format      Format
+		granularity Level
+		annotate    bool
+		style       *StyleOptions
+	}{
+		"Modules": {
+			fixture: "Graph",
+			format:  FormatDOT,
+			style:   &StyleOptions{},
+		},
+		"ModulesAnnotated": {
+			fixture:  "Graph",
+			format:   FormatDOT,
+			annotate: true,
+			style:    &StyleOptions{},
+		},
+		"ModulesFullClusters": {
+			fixture: "Graph",
+			format:  FormatDOT,
+			style:   &StyleOptions{Cluster: Full, ScaleNodes: ScaleByEdges},
+		},
+		"Packages": {
+			fixture:     "Graph",
+			format:      FormatDOT,
+			granularity: LevelPackages,
+			style:       &StyleOptions{Cluster: Parent},
+		},
+		"PackagesFullClusters": {
+			fixture:     "Graph",
+			format:      FormatDOT,
+			granularity: LevelPackages,
+			style:       &StyleOptions{Cluster: Full, ClusterParents: true},
+		},
+		"ModulesScaleFiles": {
+			fixture: "Graph",
+			format:  FormatDOT,
+			style:   &StyleOptions{ScaleNodes: ScaleByFiles, Legend: true},
+		},
+		"PackagesScalePackages": {
+			fixture:     "Graph",
+			format:      FormatDOT,
+			granularity: LevelPackages,
+			style:       &StyleOptions{ScaleNodes: ScaleByPackages},
+		},
+		"ModulesMermaid": {
+			fixture:  "Graph",
+			format:   FormatMermaid,
+			annotate: true,
+			style:    &StyleOptions{},
+		},
+		"ModulesMermaidFullClusters": {
+			fixture: "Graph",
+			format:  FormatMermaid,
+			style:   &StyleOptions{Cluster: Full},
+		},
+		"PackagesMermaid": {
+			fixture:     "Graph",
+			format:      FormatMermaid,
+			granularity: LevelPackages,
+			style:       &StyleOptions{Cluster: Parent},
+		},
+		"ModulesJSON": {
+			fixture: "Graph",
+			format:  FormatJSON,
+		},
+		"ModulesScaleFilesJSON": {
+			fixture: "Graph",
+			format:  FormatJSON,
+			style:   &StyleOptions{ScaleNodes: ScaleByFiles},
+		},
+		"PackagesJSON": {
+			fixture:     "Graph",
+			format:      FormatJSON,
+			granularity: LevelPackages,
+		},
+	}
+
+	for name := range testcases {
+		testcase := testcases[name]
+		t.Run(name, func(t *testing.T) {
+			testDir := testutil.SetupTestModule(t, filepath.Join(cwd, "testdata", testcase.fixture+".yaml"), &testutil.GraphTestDefinition{})
+
+			log := testutil.TestLogger(t)
+			g, err := depgraph.GetGraph(log, testDir, nil)
+			require.NoError(t, err)
+
+			// Print the graph twice to ensure that the output does not depend on earlier prints.
+			var outputs []string
+			for idx := 0; idx < 2; idx++ {
+				outputPath := filepath.Join(t.TempDir(), "graph"+goldenExtensions[testcase.format])
+				require.NoError(t, Print(g.Graph, &PrintConfig{
+					Log:         log.Domain(logger.PrinterDomain),
+					Granularity: testcase.granularity,
+					Annotate:    testcase.annotate,
+					Format:      testcase.format,
+					OutputPath:  outputPath,
+					Style:       testcase.style,
+				}))
+				output, err := ioutil.ReadFile(outputPath)
+				require.NoError(t, err)
+				outputs = append(outputs, string(output))
+			}
+			assert.Equal(t, outputs[0], outputs[1], "printing the same graph twice produced different output")
+
+			goldenPath := filepath.Join(cwd, "testdata", "golden", name+goldenExtensions[testcase.format])
+			if *update {
+				require.NoError(t, os.MkdirAll(filepath.Dir(goldenPath), 0o755))
+				require.NoError(t, ioutil.WriteFile(goldenPath, []byte(outputs[0]), 0o644))
+				return
+			}
+			expected, err := ioutil.ReadFile(goldenPath)
+			require.NoError(t, err, "missing golden file, run the test with '-update' to create it")
+			assert.Equal(t, string(expected), outputs[0])
+		})
+	}
+}
@@ -0,0 +1,185 @@
+package printer
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Helcaraxan/gomod/internal/depgraph"
+	"github.com/Helcaraxan/gomod/internal/graph"
+)
+
+// ColourMetric is the property of nodes that determines their colour in a printed graph.
+type ColourMetric uint8
+
+const (
+	// Colour nodes after the hash of their module's name.
+	ColourByName ColourMetric = iota
+	// Colour nodes after the age of the version of their module.
+	ColourByAge
+	// Colour nodes after the time between the version of their module and its latest available
+	// update.
+	ColourByBacklog
+	// Colour nodes after the number of nodes that directly depend on them.
+	ColourByRdeps
+	// Colour nodes after the number of packages of their module.
+	ColourByPackages
+)
+
+func (m ColourMetric) String() string {
+	return map[ColourMetric]string{
+		ColourByName:     "name",
+		ColourByAge:      "age",
+		ColourByBacklog:  "backlog",
+		ColourByRdeps:    "rdeps",
+		ColourByPackages: "packages",
+	}[m]
+}
+
+// colourScale maps the values of a metric for the printed nodes onto a gradient that ranges from
+// green for the lowest value to red for the highest one.
+type colourScale struct {
+	metric ColourMetric
+	values map[string]float64
+	min    float64
+	max    float64
+	// Whether the metric is not known for some of the nodes.
+	unknown bool
+}
+
+func newColourScale(g *graph.HierarchicalDigraph, config *PrintConfig) *colourScale {
+	if config.Style == nil || config.Style.ColourBy == ColourByName {
+		return nil
+	}
+
+	s := &colourScale{metric: config.Style.ColourBy, values: map[string]float64{}}
+	for _, node := range g.GetLevel(int(config.Granularity)).List() {
+		value, ok := metricValue(s.metric, node)
+		if !ok {
+			s.unknown = true
+			continue
+		}
+		if len(s.values) == 0 || value < s.min {
+			s.min = value
+		}
+		if len(s.values) == 0 || value > s.max {
+			s.max = value
+		}
+		s.values[node.Hash()] = value
+	}
+	return s
+}
+
+// metricValue returns the value of the metric for the node. Packages take the age and backlog of
+// their module. Higher values are coloured as more concerning.
+func metricValue(metric ColourMetric, node graph.Node) (float64, bool) {
+	var module *depgraph.Module
+	switch n := node.(type) {
+	case *depgraph.Module:
+		module = n
+	case *depgraph.Package:
+		module = n.Parent().(*depgraph.Module)
+	}
+
+	switch metric {
+	case ColourByAge:
+		if module == nil || module.Timestamp() == nil {
+			return 0, false
+		}
+		return -float64(module.Timestamp().Unix()), true
+	case ColourByBacklog:
+		if module == nil {
+			return 0, false
+		}
+		backlog, ok := module.UpdateBacklog()
+		return float64(backlog / (24 * time.Hour)), ok
+	case ColourByRdeps:
+		return float64(node.Predecessors().Len()), true
+	case ColourByPackages:
+		if parent := node.Parent(); parent != nil {
+			node = parent
+		}
+		if node.Children() == nil {
+			return 0, false
+		}
+		return float64(node.Children().Len()), true
+	default:
+		return 0, false
+	}
+}
+
+// apply overrides the colours in the DOT attributes of a node with those of the gradient. Nodes for
+// which the metric is not known are printed in grey. Nodes that are not part of the printed level,
+// such as the modules grouping packages, are placed on the gradient of the printed nodes.
+func (s *colourScale) apply(attributes []string, node graph.Node) []string {
+	value, ok := s.values[node.Hash()]
+	if !ok {
+		value, ok = metricValue(s.metric, node)
+	}
+	fill := unknownColour
+	if ok {
+		fill = gradientColour(s.fraction(value), isTestOnly(node))
+	}
+	return overrideAttributes(attributes, map[string]string{"fillcolor": fill, "fontcolor": "0.000 0.000 0.000"})
+}
+
+func (s *colourScale) fraction(value float64) float64 {
+	switch {
+	case value <= s.min:
+		return 0
+	case value >= s.max:
+		return 1
+	default:
+		return (value - s.min) / (s.max - s.min)
+	}
+}
+
+const unknownColour = "0.000 0.000 0.850"
+
+// gradientColour returns the HSV colour at the given fraction of the gradient. As with the colours
+// derived from names, test-only nodes are less saturated.
+func gradientColour(fraction float64, isTest bool) string {
+	sat := 0.7
+	if isTest {
+		sat = 0.3
+	}
+	return fmt.Sprintf("%.3f %.3f 1.000", (1-fraction)/3, sat)
+}
+
+// legend returns the entries explaining the extremes of the gradient.
+func (s *colourScale) legend() []legendEntry {
+	sample := func(fill string) []string {
+		return []string{fmt.Sprintf("fillcolor=%q", fill), `fontcolor="0.000 0.000 0.000"`}
+	}
+
+	var entries []legendEntry
+	if len(s.values) > 0 {
+		entries = append(entries, legendEntry{label: s.describe(s.min), node: sample(gradientColour(0, false))})
+		if s.max != s.min {
+			entries = append(entries, legendEntry{label: s.describe(s.max), node: sample(gradientColour(1, false))})
+		}
+	}
+	if s.unknown {
+		entries = append(entries, legendEntry{label: "unknown " + s.metric.String(), node: sample(unknownColour)})
+	}
+	return entries
+}
+
+func (s *colourScale) describe(value float64) string {
+	unit := map[ColourMetric]string{
+		ColourByBacklog:  "day",
+		ColourByRdeps:    "dependent",
+		ColourByPackages: "package",
+	}[s.metric]
+	if value != 1 {
+		unit += "s"
+	}
+
+	switch s.metric {
+	case ColourByAge:
+		return "version from " + time.Unix(int64(-value), 0).UTC().Format("2006-01-02")
+	case ColourByBacklog:
+		return fmt.Sprintf("update backlog of %d %s", int(value), unit)
+	default:
+		return fmt.Sprintf("%d %s", int(value), unit)
+	}
+}
@@ -0,0 +1,101 @@
+package printer
+
+import (
+	"encoding/xml"
+	"strings"
+
+	"github.com/Helcaraxan/gomod/internal/graph"
+)
+
+type graphMLDocument struct {
+	XMLName        xml.Name     `xml:"graphml"`
+	XMLNS          string       `xml:"xmlns,attr"`
+	XMLNSXSI       string       `xml:"xmlns:xsi,attr"`
+	SchemaLocation string       `xml:"xsi:schemaLocation,attr"`
+	Keys           []graphMLKey `xml:"key"`
+	Graph          graphMLGraph `xml:"graph"`
+}
+
+type graphMLKey struct {
+	ID   string `xml:"id,attr"`
+	For  string `xml:"for,attr"`
+	Name string `xml:"attr.name,attr"`
+	Type string `xml:"attr.type,attr"`
+}
+
+type graphMLGraph struct {
+	ID          string        `xml:"id,attr"`
+	EdgeDefault string        `xml:"edgedefault,attr"`
+	Nodes       []graphMLNode `xml:"node"`
+	Edges       []graphMLEdge `xml:"edge"`
+}
+
+type graphMLNode struct {
+	ID   string        `xml:"id,attr"`
+	Data []graphMLData `xml:"data"`
+}
+
+type graphMLEdge struct {
+	ID     string        `xml:"id,attr"`
+	Source string        `xml:"source,attr"`
+	Target string        `xml:"target,attr"`
+	Data   []graphMLData `xml:"data"`
+}
+
+type graphMLData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+var graphMLTypes = map[dataType]string{
+	dataString: "string",
+	dataBool:   "boolean",
+	dataInt:    "int",
+}
+
+// printGraphML renders the graph in the GraphML format as supported by tools such as yEd and Gephi.
+// The node and edge properties are declared as typed GraphML keys.
+func printGraphML(g *graph.HierarchicalDigraph, config *PrintConfig) ([]string, error) {
+	doc := graphMLDocument{
+		XMLNS:          "http://graphml.graphdrawing.org/xmlns",
+		XMLNSXSI:       "http://www.w3.org/2001/XMLSchema-instance",
+		SchemaLocation: "http://graphml.graphdrawing.org/xmlns http://graphml.graphdrawing.org/xmlns/1.0/graphml.xsd",
+		Graph: graphMLGraph{
+			ID:          "G",
+			EdgeDefault: "directed",
+		},
+	}
+	nodeKeys, edgeKeys := graphDataKeys(config)
+	for _, key := range nodeKeys {
+		doc.Keys = append(doc.Keys, graphMLKey{ID: "node_" + key.name, For: "node", Name: key.name, Type: graphMLTypes[key.kind]})
+	}
+	for _, key := range edgeKeys {
+		doc.Keys = append(doc.Keys, graphMLKey{ID: "edge_" + key.name, For: "edge", Name: key.name, Type: graphMLTypes[key.kind]})
+	}
+
+	nodes, edges := collectGraphData(g, config)
+	for _, node := range nodes {
+		n := graphMLNode{ID: node.id}
+		for _, d := range node.data {
+			n.Data = append(n.Data, graphMLData{Key: "node_" + d.key.name, Value: d.value})
+		}
+		doc.Graph.Nodes = append(doc.Graph.Nodes, n)
+	}
+	for _, edge := range edges {
+		e := graphMLEdge{ID: edge.id, Source: edge.source, Target: edge.target}
+		for _, d := range edge.data {
+			e.Data = append(e.Data, graphMLData{Key: "edge_" + d.key.name, Value: d.value})
+		}
+		doc.Graph.Edges = append(doc.Graph.Edges, e)
+	}
+
+	return marshalXML(doc)
+}
+
+func marshalXML(doc interface{}) ([]string, error) {
+	raw, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return strings.Split(strings.TrimSuffix(xml.Header, "\n")+"\n"+string(raw), "\n"), nil
+}
@@ -0,0 +1,358 @@
+package printer
+
+import (
+	"bytes"
+	"html/template"
+	"strings"
+
+	"github.com/Helcaraxan/gomod/internal/graph"
+)
+
+// printHTML renders the graph as a standalone HTML page. The page embeds the same data as the JSON
+// output format together with a small viewer that lays out and draws the graph as an SVG. It does
+// not rely on any external resources so that it can be viewed offline.
+func printHTML(g *graph.HierarchicalDigraph, config *PrintConfig) ([]string, error) {
+	var buf bytes.Buffer
+	if err := htmlTemplate.Execute(&buf, buildJSONDocument(g, config)); err != nil {
+		return nil, err
+	}
+	return strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n"), nil
+}
+
+var htmlTemplate = template.Must(template.New("viewer").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>gomod graph</title>
+<style>
+  html, body { margin: 0; height: 100%; overflow: hidden; font-family: sans-serif; }
+  #toolbar { position: fixed; top: 0; left: 0; right: 0; padding: 6px 8px; background: #f4f4f4; border-bottom: 1px solid #ccc; font-size: 13px; }
+  #toolbar input { width: 320px; }
+  #status { margin-left: 8px; color: #555; }
+  #info { position: fixed; top: 44px; right: 8px; max-width: 400px; padding: 8px; background: #fff; border: 1px solid #ccc; font-size: 12px; white-space: pre-wrap; display: none; }
+  #graph { display: block; width: 100%; height: 100%; cursor: grab; }
+  .node rect { stroke: #555; stroke-width: 1; }
+  .node text { font-size: 12px; pointer-events: none; }
+  .node { cursor: pointer; }
+  .edge { fill: none; stroke: #333; stroke-width: 1; }
+  .edge.indirect { stroke-dasharray: 5, 4; }
+  .edge.test { stroke: lightblue; }
+  .faded { opacity: 0.35; }
+  .node.highlighted rect { stroke: #c00; stroke-width: 3; }
+  .edge.highlighted { stroke: red; stroke-width: 2; }
+  .dim { opacity: 0.12; }
+  .node.match rect { stroke: #0a0; stroke-width: 3; }
+  .node.selected rect { stroke: #d00; stroke-width: 3; }
+  .node.dep rect { stroke: #06c; stroke-width: 2; }
+  .node.rdep rect { stroke: #c60; stroke-width: 2; }
+  .edge.dep { stroke: #06c; stroke-width: 2; }
+  .edge.rdep { stroke: #c60; stroke-width: 2; }
+</style>
+</head>
+<body>
+<svg id="graph" xmlns="http://www.w3.org/2000/svg">
+  <defs>
+    <marker id="arrow" viewBox="0 0 10 10" refX="10" refY="5" markerWidth="8" markerHeight="8" orient="auto">
+      <path d="M 0 0 L 10 5 L 0 10 z" fill="#555"></path>
+    </marker>
+  </defs>
+  <g id="viewport"><g id="edges"></g><g id="nodes"></g></g>
+</svg>
+<div id="toolbar">
+  <input id="search" type="search" placeholder="Search nodes (Enter to jump to the next match)">
+  <button id="fit">Fit</button>
+  <span id="status"></span>
+</div>
+<div id="info"></div>
+<script>
+"use strict";
+(function () {
+  var graph = {{.}};
+
+  var svgNS = "http://www.w3.org/2000/svg";
+  var svg = document.getElementById("graph");
+  var viewport = document.getElementById("viewport");
+  var info = document.getElementById("info");
+  var status = document.getElementById("status");
+  var search = document.getElementById("search");
+
+  var layerHeight = 90, nodeHeight = 28, nodeGap = 24;
+
+  var nodes = {};
+  graph.nodes.forEach(function (n) {
+    n.data = n.data || {};
+    n.succs = [];
+    n.preds = [];
+    nodes[n.id] = n;
+  });
+  graph.edges.forEach(function (e) {
+    e.data = e.data || {};
+    e.from = nodes[e.source];
+    e.to = nodes[e.target];
+    e.from.succs.push(e.to);
+    e.to.preds.push(e.from);
+  });
+
+  // Assign each node to a layer based on the longest path leading to it, ignoring the edges that
+  // close a cycle.
+  var state = {};
+  function assignLayer(n) {
+    if (state[n.id] === 2) {
+      return n.layer;
+    }
+    state[n.id] = 1;
+    var layer = 0;
+    n.preds.forEach(function (p) {
+      if (state[p.id] !== 1) {
+        layer = Math.max(layer, assignLayer(p) + 1);
+      }
+    });
+    state[n.id] = 2;
+    n.layer = layer;
+    return layer;
+  }
+  graph.nodes.forEach(assignLayer);
+
+  var layers = [];
+  graph.nodes.forEach(function (n) {
+    (layers[n.layer] = layers[n.layer] || []).push(n);
+  });
+  layers.forEach(function (layer) {
+    layer.sort(function (a, b) { return a.name < b.name ? -1 : 1; });
+    layer.forEach(function (n, idx) { n.order = idx; });
+  });
+
+  // Reduce edge crossings by repeatedly ordering the nodes of each layer by the mean position of
+  // their neighbours.
+  function barycenter(n, neighbours) {
+    if (neighbours.length === 0) {
+      return n.order;
+    }
+    var sum = 0;
+    neighbours.forEach(function (m) { sum += m.order; });
+    return sum / neighbours.length;
+  }
+  function sweep(layer, key) {
+    layer.forEach(function (n) { n.weight = barycenter(n, n[key]); });
+    layer.sort(function (a, b) { return a.weight - b.weight; });
+    layer.forEach(function (n, idx) { n.order = idx; });
+  }
+  for (var iteration = 0; iteration < 8; iteration++) {
+    layers.forEach(function (layer) { sweep(layer, "preds"); });
+    for (var idx = layers.length - 1; idx >= 0; idx--) {
+      sweep(layers[idx], "succs");
+    }
+  }
+
+  var maxWidth = 0;
+  layers.forEach(function (layer) {
+    layer.width = -nodeGap;
+    layer.forEach(function (n) {
+      n.width = Math.max(60, n.name.length * 7 + 16);
+      layer.width += n.width + nodeGap;
+    });
+    maxWidth = Math.max(maxWidth, layer.width);
+  });
+  layers.forEach(function (layer, depth) {
+    var x = (maxWidth - layer.width) / 2;
+    layer.forEach(function (n) {
+      n.x = x;
+      n.y = depth * layerHeight;
+      x += n.width + nodeGap;
+    });
+  });
+
+  function element(name, attributes, parent) {
+    var el = document.createElementNS(svgNS, name);
+    Object.keys(attributes).forEach(function (key) { el.setAttribute(key, attributes[key]); });
+    parent.appendChild(el);
+    return el;
+  }
+
+  function describe(data) {
+    var lines = [];
+    Object.keys(data).forEach(function (key) { lines.push(key + ": " + data[key]); });
+    return lines.join("\n");
+  }
+
+  var edgeLayer = document.getElementById("edges");
+  graph.edges.forEach(function (e) {
+    var x1 = e.from.x + e.from.width / 2, y1 = e.from.y + nodeHeight;
+    var x2 = e.to.x + e.to.width / 2, y2 = e.to.y;
+    var bend = Math.max(layerHeight / 2, Math.abs(y2 - y1) / 2);
+    var classes = ["edge"];
+    if (e.data.indirect) {
+      classes.push("indirect");
+    }
+    if (e.data.test_only) {
+      classes.push("test");
+    }
+    if (e.data.highlighted !== undefined) {
+      classes.push(e.data.highlighted ? "highlighted" : "faded");
+    }
+    e.el = element("path", {
+      "class": classes.join(" "),
+      "d": "M " + x1 + " " + y1 + " C " + x1 + " " + (y1 + bend) + ", " + x2 + " " + (y2 - bend) + ", " + x2 + " " + y2,
+      "marker-end": "url(#arrow)"
+    }, edgeLayer);
+    e.classes = e.el.getAttribute("class");
+    element("title", {}, e.el).textContent = e.from.name + " -> " + e.to.name + "\n" + describe(e.data);
+  });
+
+  var nodeLayer = document.getElementById("nodes");
+  graph.nodes.forEach(function (n) {
+    n.classes = "node";
+    if (n.data.highlighted !== undefined) {
+      n.classes += n.data.highlighted ? " highlighted" : " faded";
+    }
+    n.el = element("g", { "class": n.classes, "transform": "translate(" + n.x + "," + n.y + ")" }, nodeLayer);
+    element("rect", { "width": n.width, "height": nodeHeight, "rx": 6, "fill": n.fill_colour || "#fff" }, n.el);
+    var text = element("text", { "x": n.width / 2, "y": nodeHeight / 2 + 4, "text-anchor": "middle", "fill": n.text_colour || "#000" }, n.el);
+    text.textContent = n.name;
+    element("title", {}, n.el).textContent = n.name + "\n" + describe(n.data);
+    n.el.addEventListener("click", function (event) {
+      event.stopPropagation();
+      select(n);
+    });
+  });
+
+  // Pan and zoom.
+  var view = { x: 0, y: 0, k: 1 };
+  function applyView() {
+    viewport.setAttribute("transform", "translate(" + view.x + "," + view.y + ") scale(" + view.k + ")");
+  }
+  function fit() {
+    var width = svg.clientWidth, height = svg.clientHeight - 40;
+    var graphHeight = layers.length * layerHeight;
+    view.k = Math.min(1, width / (maxWidth + 40), height / (graphHeight + 40));
+    view.x = (width - maxWidth * view.k) / 2;
+    view.y = 40 + (height - graphHeight * view.k) / 2;
+    applyView();
+  }
+  function centerOn(n) {
+    view.k = Math.max(view.k, 1);
+    view.x = svg.clientWidth / 2 - (n.x + n.width / 2) * view.k;
+    view.y = svg.clientHeight / 2 - (n.y + nodeHeight / 2) * view.k;
+    applyView();
+  }
+  svg.addEventListener("wheel", function (event) {
+    event.preventDefault();
+    var factor = event.deltaY < 0 ? 1.1 : 1 / 1.1;
+    view.x = event.clientX - (event.clientX - view.x) * factor;
+    view.y = event.clientY - (event.clientY - view.y) * factor;
+    view.k *= factor;
+    applyView();
+  }, { passive: false });
+
+  var drag = null;
+  svg.addEventListener("mousedown", function (event) {
+    drag = { x: event.clientX, y: event.clientY, moved: false };
+  });
+  window.addEventListener("mousemove", function (event) {
+    if (!drag) {
+      return;
+    }
+    var dx = event.clientX - drag.x, dy = event.clientY - drag.y;
+    if (Math.abs(dx) + Math.abs(dy) > 2) {
+      drag.moved = true;
+    }
+    view.x += dx;
+    view.y += dy;
+    drag.x = event.clientX;
+    drag.y = event.clientY;
+    applyView();
+  });
+  window.addEventListener("mouseup", function () {
+    setTimeout(function () { drag = null; }, 0);
+  });
+  svg.addEventListener("click", function () {
+    if (!drag || !drag.moved) {
+      select(null);
+    }
+  });
+  document.getElementById("fit").addEventListener("click", fit);
+
+  // Highlighting of the (reverse) dependencies of a selected node.
+  function reachable(start, key) {
+    var seen = {};
+    var queue = [start];
+    while (queue.length > 0) {
+      queue.shift()[key].forEach(function (m) {
+        if (!seen[m.id] && m !== start) {
+          seen[m.id] = true;
+          queue.push(m);
+        }
+      });
+    }
+    return seen;
+  }
+
+  function select(n) {
+    if (drag && drag.moved) {
+      return;
+    }
+    if (!n) {
+      graph.nodes.forEach(function (m) { m.el.setAttribute("class", m.classes + (m.match ? " match" : "")); });
+      graph.edges.forEach(function (e) { e.el.setAttribute("class", e.classes); });
+      info.style.display = "none";
+      return;
+    }
+
+    var deps = reachable(n, "succs"), rdeps = reachable(n, "preds");
+    graph.nodes.forEach(function (m) {
+      var cls = "dim";
+      if (m === n) {
+        cls = "selected";
+      } else if (deps[m.id]) {
+        cls = "dep";
+      } else if (rdeps[m.id]) {
+        cls = "rdep";
+      }
+      m.el.setAttribute("class", m.classes + " " + cls + (m.match ? " match" : ""));
+    });
+    graph.edges.forEach(function (e) {
+      var cls = "dim";
+      if ((e.from === n || deps[e.from.id]) && deps[e.to.id]) {
+        cls = "dep";
+      } else if (rdeps[e.from.id] && (e.to === n || rdeps[e.to.id])) {
+        cls = "rdep";
+      }
+      e.el.setAttribute("class", e.classes + " " + cls);
+    });
+
+    info.textContent = n.name + "\n" + describe(n.data) +
+      "\n\ndependencies: " + Object.keys(deps).length +
+      "\nreverse dependencies: " + Object.keys(rdeps).length;
+    info.style.display = "block";
+  }
+
+  // Search.
+  var matches = [], matchIdx = -1;
+  search.addEventListener("input", function () {
+    var query = search.value.trim().toLowerCase();
+    matches = [];
+    matchIdx = -1;
+    graph.nodes.forEach(function (n) {
+      n.match = query !== "" && n.name.toLowerCase().indexOf(query) >= 0;
+      if (n.match) {
+        matches.push(n);
+      }
+      var cls = n.el.getAttribute("class").replace(/ match/g, "");
+      n.el.setAttribute("class", cls + (n.match ? " match" : ""));
+    });
+    status.textContent = query === "" ? "" : matches.length + " match(es)";
+  });
+  search.addEventListener("keydown", function (event) {
+    if (event.key === "Enter" && matches.length > 0) {
+      matchIdx = (matchIdx + 1) % matches.length;
+      centerOn(matches[matchIdx]);
+    }
+  });
+
+  status.textContent = graph.nodes.length + " nodes, " + graph.edges.length + " edges";
+  fit();
+})();
+</script>
+</body>
+</html>
+`))
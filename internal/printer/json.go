@@ -0,0 +1,83 @@
+package printer
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/Helcaraxan/gomod/internal/depgraph"
+	"github.com/Helcaraxan/gomod/internal/graph"
+)
+
+// jsonDocument is the representation of a graph used by the JSON output format. It is also the data
+// that is embedded in the HTML viewer.
+type jsonDocument struct {
+	Nodes []jsonNode `json:"nodes"`
+	Edges []jsonEdge `json:"edges"`
+}
+
+type jsonNode struct {
+	ID         string                 `json:"id"`
+	Name       string                 `json:"name"`
+	FillColour string                 `json:"fill_colour,omitempty"`
+	TextColour string                 `json:"text_colour,omitempty"`
+	Data       map[string]interface{} `json:"data,omitempty"`
+}
+
+type jsonEdge struct {
+	Source string                 `json:"source"`
+	Target string                 `json:"target"`
+	Data   map[string]interface{} `json:"data,omitempty"`
+}
+
+func buildJSONDocument(g *graph.HierarchicalDigraph, config *PrintConfig) *jsonDocument {
+	nodes, edges := collectGraphData(g, config)
+
+	doc := &jsonDocument{
+		Nodes: []jsonNode{},
+		Edges: []jsonEdge{},
+	}
+	for _, node := range nodes {
+		n := jsonNode{
+			ID:   node.id,
+			Name: node.label,
+			Data: jsonData(node.data),
+		}
+		attributes := parseDotAttributes(config.nodeAttributes(node.node, false, depgraph.HighlightNone))
+		if c, ok := attributes["fillcolor"]; ok {
+			n.FillColour = dotColourToCSS(c)
+		}
+		if c, ok := attributes["fontcolor"]; ok {
+			n.TextColour = dotColourToCSS(c)
+		}
+		doc.Nodes = append(doc.Nodes, n)
+	}
+	for _, edge := range edges {
+		doc.Edges = append(doc.Edges, jsonEdge{
+			Source: edge.source,
+			Target: edge.target,
+			Data:   jsonData(edge.data),
+		})
+	}
+	return doc
+}
+
+func jsonData(values []dataValue) map[string]interface{} {
+	if len(values) == 0 {
+		return nil
+	}
+	data := map[string]interface{}{}
+	for _, v := range values {
+		data[v.key.name] = v.raw
+	}
+	return data
+}
+
+// printJSON renders the graph as a JSON document containing a list of nodes and a list of edges
+// together with their properties.
+func printJSON(g *graph.HierarchicalDigraph, config *PrintConfig) ([]string, error) {
+	raw, err := json.MarshalIndent(buildJSONDocument(g, config), "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return strings.Split(string(raw), "\n"), nil
+}
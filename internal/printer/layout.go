@@ -0,0 +1,124 @@
+package printer
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v3"
+	"go.uber.org/zap"
+
+	"github.com/Helcaraxan/gomod/internal/depgraph"
+	"github.com/Helcaraxan/gomod/internal/graph"
+)
+
+// truncateRanks returns the part of the graph that lies within the maximum rank of the style
+// options. The rank of a node is its distance from the closest node without predecessors. Nodes
+// that can not be reached from such a node, because they are part of a cycle, are always kept.
+func truncateRanks(g *graph.HierarchicalDigraph, config *PrintConfig) (*graph.HierarchicalDigraph, error) {
+	if config.Style == nil || config.Style.MaxRank <= 0 {
+		return g, nil
+	}
+
+	nodes := g.GetLevel(int(config.Granularity)).List()
+	ranks := map[string]int{}
+	var todo []graph.Node
+	for _, node := range nodes {
+		if node.Predecessors().Len() == 0 {
+			ranks[node.Hash()] = 0
+			todo = append(todo, node)
+		}
+	}
+	for len(todo) > 0 {
+		next := todo[0]
+		todo = todo[1:]
+		for _, dep := range next.Successors().List() {
+			if _, ok := ranks[dep.Hash()]; !ok {
+				ranks[dep.Hash()] = ranks[next.Hash()] + 1
+				todo = append(todo, dep)
+			}
+		}
+	}
+
+	var keep []string
+	for _, node := range nodes {
+		if rank, ok := ranks[node.Hash()]; !ok || rank <= config.Style.MaxRank {
+			keep = append(keep, node.Hash())
+		}
+	}
+	if len(keep) == len(nodes) {
+		return g, nil
+	}
+	config.Log.Debug("Truncating graph to maximum rank.", zap.Int("max-rank", config.Style.MaxRank), zap.Int("dropped", len(nodes)-len(keep)))
+	return g.Subgraph(keep)
+}
+
+// printRankConstraintsToDot places the main module, or its packages, on the top rank when pinned
+// and the nodes matching each of the same-rank patterns on a common rank.
+func printRankConstraintsToDot(g *graph.HierarchicalDigraph, config *PrintConfig) []string {
+	if config.Style == nil {
+		return nil
+	}
+
+	nodes := g.GetLevel(int(config.Granularity)).List()
+	var constraints []string
+	if config.Style.PinMain {
+		var main []graph.Node
+		for _, node := range nodes {
+			if isMain(node) {
+				main = append(main, node)
+			}
+		}
+		if len(main) > 0 {
+			constraints = append(constraints, rankConstraint("source", main))
+		}
+	}
+	for _, pattern := range config.Style.SameRank {
+		var group []graph.Node
+		for _, node := range nodes {
+			if nodeMatches(pattern, node) {
+				group = append(group, node)
+			}
+		}
+		if len(group) > 1 {
+			constraints = append(constraints, rankConstraint("same", group))
+		}
+	}
+	return constraints
+}
+
+func rankConstraint(rank string, nodes []graph.Node) string {
+	names := make([]string, 0, len(nodes))
+	for _, node := range nodes {
+		names = append(names, fmt.Sprintf("%q", node.Name()))
+	}
+	return fmt.Sprintf("  {rank=%s; %s}", rank, strings.Join(names, "; "))
+}
+
+// hasRankConstraints indicates whether the style options place nodes on specific ranks.
+func (s *StyleOptions) hasRankConstraints() bool {
+	return s != nil && (s.PinMain || len(s.SameRank) > 0)
+}
+
+func isMain(node graph.Node) bool {
+	switch n := node.(type) {
+	case *depgraph.Module:
+		return n.Info.Main
+	case *depgraph.Package:
+		return n.Parent().(*depgraph.Module).Info.Main
+	default:
+		return false
+	}
+}
+
+// nodeMatches indicates whether the glob pattern matches the name of the node or, for packages,
+// the name of their module.
+func nodeMatches(pattern string, node graph.Node) bool {
+	if match, _ := doublestar.Match(pattern, node.Name()); match {
+		return true
+	}
+	if parent := node.Parent(); parent != nil {
+		match, _ := doublestar.Match(pattern, parent.Name())
+		return match
+	}
+	return false
+}
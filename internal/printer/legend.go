@@ -0,0 +1,197 @@
+package printer
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Helcaraxan/gomod/internal/depgraph"
+	"github.com/Helcaraxan/gomod/internal/graph"
+)
+
+// legendEntry explains one of the visual encodings used in a printed graph by means of a sample
+// node or a sample edge with the corresponding DOT attributes.
+type legendEntry struct {
+	label string
+	node  []string
+	edge  []string
+}
+
+// legendNodeKeys are the DOT attributes of nodes that are reproduced on the legend's samples.
+var legendNodeKeys = []string{"fillcolor", "fontcolor", "color", "penwidth", "peripheries"}
+
+// computeLegend determines which visual encodings are present in the printed graph and returns an
+// entry explaining each of them. Node encodings are listed before edge encodings, each in a fixed
+// order so that the legend is stable across graphs.
+func computeLegend(g *graph.HierarchicalDigraph, config *PrintConfig, clusters *graphClusters) []legendEntry {
+	kind, colouring := "module", "module, coloured after its name"
+	if config.Granularity == LevelPackages {
+		kind, colouring = "package", "package, coloured like its module"
+	}
+
+	var themed map[int][]string
+	if config.Theme != nil {
+		themed = map[int][]string{}
+	}
+	var dependency, testDependency, collapsed, highlighted, faded []string
+	var indirect, testEdge, weighted, merged bool
+	var highlightedEdge, fadedEdge []string
+	for _, node := range g.GetLevel(int(config.Granularity)).List() {
+		highlight := config.nodeHighlight(node)
+		attributes := config.nodeAttributes(node, false, depgraph.HighlightNone)
+
+		_, isCollapsed := node.(*depgraph.Collapsed)
+		if idx, rule := config.Theme.match(node); rule != nil {
+			if _, ok := themed[idx]; !ok {
+				themed[idx] = pickAttributes(attributes, legendNodeKeys...)
+			}
+		} else if isCollapsed {
+			if collapsed == nil {
+				collapsed = pickAttributes(attributes, legendNodeKeys...)
+			}
+		} else if isTestOnly(node) {
+			if testDependency == nil {
+				testDependency = pickAttributes(attributes, legendNodeKeys...)
+			}
+		} else if dependency == nil {
+			dependency = pickAttributes(attributes, legendNodeKeys...)
+		}
+
+		if highlight == depgraph.Highlighted && highlighted == nil {
+			highlighted = pickAttributes(config.nodeAttributes(node, false, highlight), legendNodeKeys...)
+		} else if highlight == depgraph.Faded && faded == nil {
+			faded = pickAttributes(config.nodeAttributes(node, false, highlight), legendNodeKeys...)
+		}
+
+		a, ok := node.(annotated)
+		if !ok {
+			continue
+		}
+		for _, dep := range node.Successors().List() {
+			highlight := config.edgeHighlight(node, dep)
+			attributes := a.EdgeAttributes(dep, false, false, highlight)
+			edge := parseDotAttributes(attributes)
+			indirect = indirect || edge["style"] == "dashed"
+			testEdge = testEdge || edge["color"] == "lightblue" //nolint:misspell
+			weighted = weighted || (edge["penwidth"] != "" && highlight != depgraph.Highlighted)
+			merged = merged || strings.HasSuffix(edge["label"], " edges</font>")
+			if highlight == depgraph.Highlighted && highlightedEdge == nil {
+				highlightedEdge = pickAttributes(attributes, "color", "penwidth")
+			} else if highlight == depgraph.Faded && fadedEdge == nil {
+				fadedEdge = pickAttributes(attributes, "color")
+			}
+		}
+	}
+
+	var entries []legendEntry
+	if config.Theme != nil {
+		for idx, rule := range config.Theme.Rules {
+			if sample, ok := themed[idx]; ok {
+				label := rule.Label
+				if label == "" {
+					label = "theme rule " + strconv.Itoa(idx+1)
+				}
+				entries = append(entries, legendEntry{label: label, node: sample})
+			}
+		}
+	}
+	if config.scale != nil {
+		entries = append(entries, config.scale.legend()...)
+	} else if dependency != nil {
+		entries = append(entries, legendEntry{label: colouring, node: dependency})
+	}
+	if testDependency != nil {
+		entries = append(entries, legendEntry{label: "test-only " + kind, node: testDependency})
+	}
+	if collapsed != nil {
+		entries = append(entries, legendEntry{label: "collapsed " + kind + "s", node: collapsed})
+	}
+	if highlighted != nil {
+		entries = append(entries, legendEntry{label: "highlighted " + kind, node: highlighted})
+	}
+	if faded != nil {
+		entries = append(entries, legendEntry{label: "other " + kind, node: faded})
+	}
+	if config.Style != nil && config.Style.ScaleNodes != ScaleOff {
+		entries = append(entries, legendEntry{label: config.Style.ScaleNodes.legendLabel(), node: []string{}})
+	}
+	if clusters.parentGroups != nil {
+		entries = append(entries, legendEntry{label: "packages of the same module", node: []string{`style="rounded"`, "penwidth=2"}})
+	}
+	for _, cluster := range clusters.clusterList {
+		if len(cluster.members) > 1 {
+			entries = append(entries, legendEntry{label: kind + "s with the same dependents", node: []string{`style="rounded"`, "color=blue"}}) //nolint:misspell
+			break
+		}
+	}
+
+	if indirect {
+		entries = append(entries, legendEntry{label: "indirect dependency", edge: []string{"style=dashed"}})
+	}
+	if testEdge {
+		entries = append(entries, legendEntry{label: "test-only dependency", edge: []string{"color=lightblue"}}) //nolint:misspell
+	}
+	if weighted {
+		entries = append(entries, legendEntry{label: "thicker for more package imports", edge: []string{"penwidth=3"}})
+	}
+	if merged {
+		entries = append(entries, legendEntry{label: "merged edges with their count", edge: []string{`label=<<font point-size="10">N edges</font>>`}})
+	}
+	if highlightedEdge != nil {
+		entries = append(entries, legendEntry{label: "highlighted dependency", edge: highlightedEdge})
+	}
+	if fadedEdge != nil {
+		entries = append(entries, legendEntry{label: "other dependency", edge: fadedEdge})
+	}
+	return entries
+}
+
+func isTestOnly(node graph.Node) bool {
+	a, ok := node.(depgraph.DataAnnotated)
+	if !ok {
+		return false
+	}
+	testOnly, _ := a.NodeData()["test_only"].(bool)
+	return testOnly
+}
+
+// pickAttributes returns the DOT attributes with the given keys, in the order of the keys.
+func pickAttributes(attributes []string, keys ...string) []string {
+	parsed := parseDotAttributes(attributes)
+	picked := []string{}
+	for _, key := range keys {
+		if value, ok := parsed[key]; ok {
+			picked = append(picked, fmt.Sprintf("%s=%q", key, value))
+		}
+	}
+	return picked
+}
+
+// printLegendToDot renders the legend as a cluster of sample nodes and edges. Edge samples point
+// at a node that only contains their description.
+func printLegendToDot(entries []legendEntry) []string {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	dot := []string{
+		"  subgraph cluster_legend {",
+		"    label=\"Legend\"",
+		"    graph [style=rounded,color=black]",
+	}
+	for idx, entry := range entries {
+		id := "legend_" + strconv.Itoa(idx)
+		if entry.node != nil {
+			attributes := append([]string{fmt.Sprintf("label=%q", entry.label)}, entry.node...)
+			dot = append(dot, fmt.Sprintf("    %q [%s]", id, strings.Join(attributes, ",")))
+			continue
+		}
+		dot = append(
+			dot,
+			fmt.Sprintf("    %q [shape=point,style=invis]", id),
+			fmt.Sprintf("    %q [shape=plaintext,style=\"\",label=%q]", id+"_label", entry.label),
+			fmt.Sprintf("    %q -> %q [%s]", id, id+"_label", strings.Join(entry.edge, ",")),
+		)
+	}
+	return append(dot, "  }")
+}
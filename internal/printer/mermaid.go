@@ -0,0 +1,336 @@
+package printer
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/Helcaraxan/gomod/internal/depgraph"
+	"github.com/Helcaraxan/gomod/internal/graph"
+)
+
+// printMermaid renders the graph as a Mermaid flowchart. The selection of nodes and edges, as well
+// as any clustering, is identical to the DOT output. The DOT attributes provided by annotated nodes
+// are translated into their Mermaid counterparts: fill and text colours become node classes while
+// dashed, coloured and highlighted edges are expressed via link types and link styles.
+func printMermaid(g *graph.HierarchicalDigraph, config *PrintConfig) []string {
+	nodes := g.GetLevel(int(config.Granularity)).List()
+	m := &mermaidPrinter{
+		config:     config,
+		nodeIDs:    map[string]string{},
+		clusterIDs: map[*graphCluster]string{},
+		classIDs:   map[string]string{},
+		classNodes: map[string][]string{},
+		linkStyles: map[string][]string{},
+	}
+	for idx, node := range nodes {
+		m.nodeIDs[node.Hash()] = "n" + strconv.Itoa(idx)
+	}
+
+	direction := "TB"
+	if config.Style != nil && config.Style.Direction != "" {
+		direction = config.Style.Direction
+	}
+	fileContent := []string{
+		"flowchart " + direction,
+	}
+
+	clusters := computeGraphClusters(g, config)
+	for idx, cluster := range clusters.clusterList {
+		m.clusterIDs[cluster] = "c" + strconv.Itoa(idx)
+	}
+	if clusters.parentGroups != nil {
+		for idx, group := range clusters.parentGroups {
+			fileContent = append(fileContent, m.printParentGroup("p"+strconv.Itoa(idx), group)...)
+		}
+	} else {
+		for _, cluster := range clusters.clusterList {
+			fileContent = append(fileContent, m.printCluster(cluster)...)
+		}
+	}
+
+	for _, node := range nodes {
+		fileContent = append(fileContent, m.printEdges(node, clusters)...)
+	}
+	if config.Style != nil && config.Style.Legend {
+		fileContent = append(fileContent, m.printLegend(computeLegend(g, config, clusters))...)
+	}
+
+	for _, class := range m.classList {
+		fileContent = append(
+			fileContent,
+			fmt.Sprintf("  classDef %s %s", m.classIDs[class], class),
+			fmt.Sprintf("  class %s %s", strings.Join(m.classNodes[class], ","), m.classIDs[class]),
+		)
+	}
+	fileContent = append(fileContent, m.subgraphStyles...)
+	for _, style := range m.linkStyleList {
+		fileContent = append(fileContent, fmt.Sprintf("  linkStyle %s %s", strings.Join(m.linkStyles[style], ","), style))
+	}
+	return fileContent
+}
+
+type mermaidPrinter struct {
+	config *PrintConfig
+
+	nodeIDs    map[string]string
+	clusterIDs map[*graphCluster]string
+
+	// Node classes in order of first use, mapped to their identifier and the nodes using them.
+	classList  []string
+	classIDs   map[string]string
+	classNodes map[string][]string
+
+	// Link styles in order of first use, mapped to the indices of the links using them.
+	linkCount     int
+	linkStyleList []string
+	linkStyles    map[string][]string
+
+	// Styles of the subgraphs grouping nodes by parent.
+	subgraphStyles []string
+}
+
+func (m *mermaidPrinter) printParentGroup(id string, group *parentGroup) []string {
+	lines := []string{fmt.Sprintf("  subgraph %s [\"%s\"]", id, escapeMermaidText(group.parent.Name()))}
+	for _, cluster := range group.clusters {
+		for _, line := range m.printCluster(cluster) {
+			lines = append(lines, "  "+line)
+		}
+	}
+
+	if c, ok := parseDotAttributes(m.config.nodeAttributes(group.parent, false, depgraph.HighlightNone))["fillcolor"]; ok {
+		m.subgraphStyles = append(m.subgraphStyles, fmt.Sprintf("  style %s stroke:%s,stroke-width:2px", id, dotColourToCSS(c)))
+	}
+	return append(lines, "  end")
+}
+
+func (m *mermaidPrinter) printCluster(cluster *graphCluster) []string {
+	if len(cluster.members) == 0 {
+		m.config.Log.Warn("Found an empty node cluster associated with.", zap.String("cluster", cluster.name()), zap.String("hash", cluster.hash))
+		return nil
+	} else if len(cluster.members) == 1 {
+		return []string{"  " + m.printNode(cluster.members[0])}
+	}
+
+	lines := []string{fmt.Sprintf("  subgraph %s [\" \"]", m.clusterIDs[cluster])}
+	for _, node := range cluster.members {
+		lines = append(lines, "    "+m.printNode(node))
+	}
+	return append(lines, "  end")
+}
+
+func (m *mermaidPrinter) printNode(node graph.Node) string {
+	id := m.nodeIDs[node.Hash()]
+	label := node.Name()
+
+	if _, ok := node.(annotated); ok {
+		attributes := parseDotAttributes(m.config.nodeAttributes(node, m.config.Annotate, m.config.nodeHighlight(node)))
+		if l, ok := attributes["label"]; ok {
+			label = htmlLabelToMermaid(l)
+		}
+		m.styleNode(id, attributes)
+	}
+	return fmt.Sprintf("%s(\"%s\")", id, escapeMermaidText(label))
+}
+
+// styleNode assigns the node with the given identifier to the class corresponding to its DOT
+// attributes.
+func (m *mermaidPrinter) styleNode(id string, attributes map[string]string) {
+	var class []string
+	if c, ok := attributes["fillcolor"]; ok {
+		class = append(class, "fill:"+dotColourToCSS(c))
+	}
+	if c, ok := attributes["fontcolor"]; ok {
+		class = append(class, "color:"+dotColourToCSS(c))
+	}
+	if c, ok := attributes["color"]; ok {
+		class = append(class, "stroke:"+dotColourToCSS(c))
+	}
+	if w, ok := attributes["penwidth"]; ok {
+		class = append(class, "stroke-width:"+w+"px")
+	}
+	if len(class) > 0 {
+		m.addClass(strings.Join(class, ","), id)
+	}
+}
+
+func (m *mermaidPrinter) printEdges(node graph.Node, clusters *graphClusters) []string {
+	clustersReached := map[int]struct{}{}
+
+	var lines []string
+	for _, dep := range node.Successors().List() {
+		cluster, ok := clusters.clusterMap[dep.Hash()]
+		if !ok {
+			m.config.Log.Error("No cluster reference found for dependency.", zap.String("node", node.Hash()), zap.String("dep", dep.Hash()))
+			continue
+		} else if _, ok = clustersReached[cluster.id]; ok {
+			continue
+		}
+		clustersReached[cluster.id] = struct{}{}
+
+		target := m.nodeIDs[dep.Hash()]
+		length := 1
+		if minLength := clusters.clusterDepthMap(dep.Hash())[node.Hash()]; minLength > 1 {
+			length = minLength
+		}
+
+		annotate, weights := m.config.Annotate, m.config.Style.edgeWeights()
+		if len(cluster.members) > 1 {
+			annotate, weights = false, false
+			target = m.clusterIDs[cluster]
+		}
+
+		var attributes map[string]string
+		if a, ok := node.(annotated); ok {
+			attributes = parseDotAttributes(a.EdgeAttributes(dep, annotate, weights, m.config.edgeHighlight(node, dep)))
+		}
+
+		lines = append(lines, fmt.Sprintf("  %s %s %s", m.nodeIDs[node.Hash()], m.link(attributes, length), target))
+	}
+	return lines
+}
+
+// link returns the Mermaid link of the given length corresponding to an edge's DOT attributes and
+// records its style.
+func (m *mermaidPrinter) link(attributes map[string]string, length int) string {
+	link := "--" + strings.Repeat("-", length-1) + ">"
+	if attributes["style"] == "dashed" {
+		link = "-." + strings.Repeat(".", length-1) + "->"
+	}
+	if l, ok := attributes["label"]; ok {
+		link += "|\"" + escapeMermaidText(htmlLabelToMermaid(l)) + "\"|"
+	}
+	var style []string
+	if c, ok := attributes["color"]; ok {
+		style = append(style, "stroke:"+dotColourToCSS(c))
+	}
+	if w, ok := attributes["penwidth"]; ok {
+		style = append(style, "stroke-width:"+w+"px")
+	}
+	if len(style) > 0 {
+		m.addLinkStyle(strings.Join(style, ","), m.linkCount)
+	}
+	m.linkCount++
+	return link
+}
+
+// printLegend renders the legend as a subgraph of sample nodes and edges. Edge samples point at a
+// borderless node that only contains their description.
+func (m *mermaidPrinter) printLegend(entries []legendEntry) []string {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	lines := []string{"  subgraph legend [\"Legend\"]"}
+	for idx, entry := range entries {
+		id := "l" + strconv.Itoa(idx)
+		if entry.node != nil {
+			m.styleNode(id, parseDotAttributes(entry.node))
+			lines = append(lines, fmt.Sprintf("    %s(\"%s\")", id, escapeMermaidText(entry.label)))
+			continue
+		}
+		m.addClass("fill:none,stroke:none", id)
+		m.addClass("fill:none,stroke:none", id+"t")
+		lines = append(lines, fmt.Sprintf("    %s[\" \"] %s %st[\"%s\"]", id, m.link(parseDotAttributes(entry.edge), 1), id, escapeMermaidText(entry.label)))
+	}
+	return append(lines, "  end")
+}
+
+func (m *mermaidPrinter) addClass(class string, nodeID string) {
+	if _, ok := m.classIDs[class]; !ok {
+		m.classIDs[class] = "s" + strconv.Itoa(len(m.classList))
+		m.classList = append(m.classList, class)
+	}
+	m.classNodes[class] = append(m.classNodes[class], nodeID)
+}
+
+func (m *mermaidPrinter) addLinkStyle(style string, linkIdx int) {
+	if _, ok := m.linkStyles[style]; !ok {
+		m.linkStyleList = append(m.linkStyleList, style)
+	}
+	m.linkStyles[style] = append(m.linkStyles[style], strconv.Itoa(linkIdx))
+}
+
+// parseDotAttributes converts a list of 'key=value' DOT attributes into a map. Surrounding quotes
+// of regular values and the angle brackets of HTML-like labels are removed.
+func parseDotAttributes(attributes []string) map[string]string {
+	parsed := map[string]string{}
+	for _, attribute := range attributes {
+		idx := strings.Index(attribute, "=")
+		if idx < 0 {
+			continue
+		}
+		key, value := attribute[:idx], attribute[idx+1:]
+		if len(value) >= 2 && (value[0] == '"' && value[len(value)-1] == '"' || value[0] == '<' && value[len(value)-1] == '>') {
+			value = value[1 : len(value)-1]
+		}
+		parsed[key] = value
+	}
+	return parsed
+}
+
+var dotFontTag = regexp.MustCompile(`<font[^>]*>`)
+
+// htmlLabelToMermaid translates the HTML-like labels supported by DOT into the subset of HTML that
+// is accepted by Mermaid.
+func htmlLabelToMermaid(label string) string {
+	label = strings.ReplaceAll(label, "<br />", "<br/>")
+	label = dotFontTag.ReplaceAllString(label, "<small>")
+	return strings.ReplaceAll(label, "</font>", "</small>")
+}
+
+func escapeMermaidText(text string) string {
+	return strings.ReplaceAll(text, `"`, "#quot;")
+}
+
+// dotColourToCSS converts a DOT colour specification into one that can be used in CSS. DOT's
+// "H S V" triplets are converted to their hexadecimal RGB equivalent while any other value, such as
+// a named colour, is returned as is.
+func dotColourToCSS(colour string) string {
+	fields := strings.FieldsFunc(colour, func(r rune) bool { return r == ' ' || r == ',' })
+	if len(fields) != 3 {
+		return colour
+	}
+
+	var hsv [3]float64
+	for idx, field := range fields {
+		v, err := strconv.ParseFloat(field, 64)
+		if err != nil {
+			return colour
+		}
+		hsv[idx] = math.Max(0, math.Min(1, v))
+	}
+
+	r, g, b := hsvToRGB(hsv[0], hsv[1], hsv[2])
+	return fmt.Sprintf("#%02x%02x%02x", r, g, b)
+}
+
+func hsvToRGB(h float64, s float64, v float64) (r uint8, g uint8, b uint8) {
+	sector := math.Mod(h*6, 6)
+	c := v * s
+	x := c * (1 - math.Abs(math.Mod(sector, 2)-1))
+
+	var rf, gf, bf float64
+	switch int(sector) {
+	case 0:
+		rf, gf, bf = c, x, 0
+	case 1:
+		rf, gf, bf = x, c, 0
+	case 2:
+		rf, gf, bf = 0, c, x
+	case 3:
+		rf, gf, bf = 0, x, c
+	case 4:
+		rf, gf, bf = x, 0, c
+	default:
+		rf, gf, bf = c, 0, x
+	}
+
+	base := v - c
+	toByte := func(f float64) uint8 { return uint8(math.Round((f + base) * 255)) }
+	return toByte(rf), toByte(gf), toByte(bf)
+}
@@ -1,6 +1,7 @@
 package printer
 
 import (
+	"bufio"
 	"fmt"
 	"math"
 	"os"
@@ -21,6 +22,41 @@ const (
 	LevelPackages
 )
 
+// Format in which to print the graph.
+type Format uint8
+
+const (
+	// The DOT language used by GraphViz.
+	FormatDOT Format = iota
+	// Mermaid flowchart syntax which is rendered natively by many Markdown viewers.
+	FormatMermaid
+	// GraphML for consumption by graph analysis tools such as yEd.
+	FormatGraphML
+	// GEXF for consumption by graph analysis tools such as Gephi.
+	FormatGEXF
+	// JSON document listing nodes and edges together with their properties.
+	FormatJSON
+	// Standalone HTML page with an interactive viewer for the graph.
+	FormatHTML
+	// Comma-separated tables of the graph's nodes and edges.
+	FormatCSV
+	// Tab-separated tables of the graph's nodes and edges.
+	FormatTSV
+)
+
+func (f Format) String() string {
+	return map[Format]string{
+		FormatDOT:     "dot",
+		FormatMermaid: "mermaid",
+		FormatGraphML: "graphml",
+		FormatGEXF:    "gexf",
+		FormatJSON:    "json",
+		FormatHTML:    "html",
+		FormatCSV:     "csv",
+		FormatTSV:     "tsv",
+	}[f]
+}
+
 // PrintConfig allows for the specification of parameters that should be passed to the Print
 // function of a Graph.
 type PrintConfig struct {
@@ -32,20 +68,97 @@ type PrintConfig struct {
 
 	// Annotate edges and nodes with their respective versions.
 	Annotate bool
+	// Hashes of the nodes that should be highlighted. When non-nil all other nodes, as well as all
+	// edges that do not connect two highlighted nodes, are faded.
+	Highlight map[string]bool
+	// Colours to use for the nodes of the Graph instead of those derived from their hash.
+	Theme *Theme
+	// Format in which the Graph should be printed.
+	Format Format
+	// Columns to include when printing the Graph in a tabular format. All columns are printed if
+	// empty.
+	Columns []string
 	// Path at which the printed version of the Graph should be stored. If set to a nil-string a
 	// temporary file will be created.
 	OutputPath string
 	// Options for generating a visual representation of the Graph. If the field is non-nil, print
 	// out an image file using GraphViz, if false print out the graph in DOT format.
 	Style *StyleOptions
+
+	// Gradient used to colour nodes when a metric is selected by the style options.
+	scale *colourScale
+	// Code footprint of the nodes when it determines their size.
+	sizes *sizeScale
 }
 
 type StyleOptions struct {
-	// Scale nodes according to the number of their successors and predecssors.
-	ScaleNodes bool
+	// Property after which to scale the size of nodes, such as the number of their successors and
+	// predecessors or the number of Go files of their packages.
+	ScaleNodes NodeScale
 	// Level at which to cluster nodes in the printed graph. This can be very beneficial for larger
 	// dependency graphs that might be unreadable with the default settings.
 	Cluster ClusterLevel
+	// Group nodes that have the same parent in a labelled subgraph in addition to the clustering
+	// performed for the Shared and Full levels. This is implied by the Parent level.
+	ClusterParents bool
+	// Add a legend explaining the colours and line styles used in the printed graph.
+	Legend bool
+	// Metric that determines the colours of nodes. All metrics other than the name replace the
+	// colours derived from the name of a node's module with a gradient.
+	ColourBy ColourMetric
+	// Direction in which ranks are laid out: 'TB' (the default), 'BT', 'LR' or 'RL'.
+	Direction string
+	// Minimum distance between ranks in inches. When zero it is derived from the size of the graph
+	// if nodes are scaled and left to GraphViz otherwise.
+	RankSep float64
+	// How edges are drawn, such as 'ortho', 'polyline' or 'spline'. When empty orthogonal edges
+	// are used unless the graph is annotated.
+	Splines string
+	// Label the edges between modules with the number of package imports that they represent.
+	EdgeWeights bool
+	// Font used for the labels of nodes, edges and subgraphs.
+	Font string
+	// DOT attributes that are set on the nodes matching a pattern. These take precedence over all
+	// other attributes of a node.
+	NodeOverrides []NodeOverride
+	// Glob patterns of which the matching nodes are placed on the same rank, one rank per pattern.
+	SameRank []string
+	// Place the main module, or its packages, on the top rank.
+	PinMain bool
+	// Only print nodes that are at most this many edges away from a node without predecessors.
+	// Zero means unlimited.
+	MaxRank int
+}
+
+// NodeOverride sets DOT attributes, such as 'shape' or 'fillcolor', on the nodes matching a glob
+// pattern. The pattern is matched against the node's name and, for packages, against the name of
+// their module.
+type NodeOverride struct {
+	Pattern    string
+	Attributes map[string]string
+}
+
+// nodeOverrides returns the attributes of the first override matching the node, if any.
+func (s *StyleOptions) nodeOverrides(node graph.Node) map[string]string {
+	if s == nil {
+		return nil
+	}
+	for _, override := range s.NodeOverrides {
+		if nodeMatches(override.Pattern, node) {
+			return override.Attributes
+		}
+	}
+	return nil
+}
+
+// clusterParents indicates whether nodes should be grouped by their parent.
+func (s *StyleOptions) clusterParents() bool {
+	return s != nil && (s.Cluster == Parent || s.ClusterParents)
+}
+
+// edgeWeights indicates whether edges should be labelled with their weight.
+func (s *StyleOptions) edgeWeights() bool {
+	return s != nil && s.EdgeWeights
 }
 
 // Level at which to performing clustering when generating the image of the
@@ -63,44 +176,173 @@ const (
 	Full
 )
 
+func (c *PrintConfig) nodeHighlight(node graph.Node) depgraph.Highlight {
+	switch {
+	case c.Highlight == nil:
+		return depgraph.HighlightNone
+	case c.Highlight[node.Hash()]:
+		return depgraph.Highlighted
+	default:
+		return depgraph.Faded
+	}
+}
+
+func (c *PrintConfig) edgeHighlight(source graph.Node, target graph.Node) depgraph.Highlight {
+	switch {
+	case c.Highlight == nil:
+		return depgraph.HighlightNone
+	case c.Highlight[source.Hash()] && c.Highlight[target.Hash()]:
+		return depgraph.Highlighted
+	default:
+		return depgraph.Faded
+	}
+}
+
 // Print takes in a PrintConfig struct and dumps the content of a HierarchicalDigraph instance
 // according to parameters.
 func Print(g *graph.HierarchicalDigraph, config *PrintConfig) error {
+	g, err := truncateRanks(g, config)
+	if err != nil {
+		config.Log.Error("Failed to truncate graph.", zap.Int("max-rank", config.Style.MaxRank), zap.Error(err))
+		return err
+	}
+	config.scale = newColourScale(g, config)
+	config.sizes = newSizeScale(g, config)
+
+	var fileContent []string
+	switch config.Format {
+	case FormatCSV, FormatTSV:
+		return printTables(g, config)
+	case FormatMermaid:
+		fileContent = printMermaid(g, config)
+	case FormatGraphML:
+		fileContent, err = printGraphML(g, config)
+	case FormatGEXF:
+		fileContent, err = printGEXF(g, config)
+	case FormatJSON:
+		fileContent, err = printJSON(g, config)
+	case FormatHTML:
+		fileContent, err = printHTML(g, config)
+	default:
+		return streamOutput(config, config.OutputPath, func(w *bufio.Writer) error {
+			return printDot(w, g, config)
+		})
+	}
+	if err != nil {
+		config.Log.Error("Failed to generate graph.", zap.Stringer("format", config.Format), zap.Error(err))
+		return err
+	}
+	return writeOutput(config, config.OutputPath, fileContent)
+}
+
+// writeOutput writes the given lines to the file at the specified path or to the terminal if the
+// path is empty.
+func writeOutput(config *PrintConfig, path string, content []string) error {
+	return streamOutput(config, path, func(w *bufio.Writer) error {
+		for _, line := range content {
+			if _, err := w.WriteString(line); err != nil {
+				return err
+			}
+			if err := w.WriteByte('\n'); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// streamOutput passes a buffered writer for the file at the specified path, or for the terminal if
+// the path is empty, to the given function so that content can be written as it is generated.
+func streamOutput(config *PrintConfig, path string, write func(w *bufio.Writer) error) error {
 	var err error
 	out := os.Stdout
-	if len(config.OutputPath) > 0 {
-		if out, err = util.PrepareOutputPath(config.Log, config.OutputPath); err != nil {
+	if len(path) > 0 {
+		if out, err = util.PrepareOutputPath(config.Log, path); err != nil {
 			return err
 		}
 		defer func() {
 			_ = out.Close()
 		}()
-		config.Log.Debug("Writing DOT graph.", zap.String("path", config.OutputPath))
+		config.Log.Debug("Writing graph.", zap.Stringer("format", config.Format), zap.String("path", path))
 	} else {
-		config.Log.Debug("Writing DOT graph to terminal.")
+		config.Log.Debug("Writing graph to terminal.", zap.Stringer("format", config.Format))
 	}
 
-	fileContent := []string{
-		"strict digraph {",
+	w := bufio.NewWriterSize(out, outputBufferSize)
+	if err = write(w); err == nil {
+		err = w.Flush()
 	}
-	fileContent = append(fileContent, determineGlobalOptions(g, config)...)
+	if err != nil {
+		config.Log.Error("Failed to write graph.", zap.Stringer("format", config.Format), zap.Error(err))
+		return fmt.Errorf("could not write to %q", out.Name())
+	}
+	return nil
+}
 
-	clusters := computeGraphClusters(g, config)
-	for _, cluster := range clusters.clusterList {
-		fileContent = append(fileContent, printClusterToDot(cluster, config))
+const outputBufferSize = 64 * 1024
+
+// dotWriter writes lines of DOT content, each prefixed with the current indentation. The first
+// error that occurs is retained and all subsequent writes are skipped.
+type dotWriter struct {
+	w      *bufio.Writer
+	indent string
+	err    error
+}
+
+func (d *dotWriter) line(parts ...string) {
+	if d.err != nil {
+		return
+	}
+	_, d.err = d.w.WriteString(d.indent)
+	for _, part := range parts {
+		if d.err == nil {
+			_, d.err = d.w.WriteString(part)
+		}
 	}
+	if d.err == nil {
+		d.err = d.w.WriteByte('\n')
+	}
+}
 
-	for _, node := range g.GetLevel(int(config.Granularity)).List() {
-		fileContent = append(fileContent, printEdgesToDot(config, node, clusters)...)
+func (d *dotWriter) linef(format string, args ...interface{}) {
+	if d.err != nil {
+		return
 	}
+	d.line(fmt.Sprintf(format, args...))
+}
 
-	fileContent = append(fileContent, "}")
+func printDot(w *bufio.Writer, g *graph.HierarchicalDigraph, config *PrintConfig) error {
+	d := &dotWriter{w: w}
+	d.line("strict digraph {")
+	for _, option := range determineGlobalOptions(g, config) {
+		d.line(option)
+	}
 
-	if _, err = out.WriteString(strings.Join(fileContent, "\n") + "\n"); err != nil {
-		config.Log.Error("Failed to write DOT file.", zap.Error(err))
-		return fmt.Errorf("could not write to %q", out.Name())
+	clusters := computeGraphClusters(g, config)
+	if clusters.parentGroups != nil {
+		for _, group := range clusters.parentGroups {
+			printParentGroupToDot(d, group, config)
+		}
+	} else {
+		for _, cluster := range clusters.clusterList {
+			printClusterToDot(d, cluster, config)
+		}
 	}
-	return nil
+
+	for _, node := range clusters.levelNodes.List() {
+		printEdgesToDot(d, config, node, clusters)
+	}
+	for _, constraint := range printRankConstraintsToDot(g, config) {
+		d.line(constraint)
+	}
+
+	if config.Style != nil && config.Style.Legend {
+		for _, line := range printLegendToDot(computeLegend(g, config, clusters)) {
+			d.line(line)
+		}
+	}
+	d.line("}")
+	return d.err
 }
 
 func determineGlobalOptions(g *graph.HierarchicalDigraph, config *PrintConfig) []string {
@@ -109,17 +351,36 @@ func determineGlobalOptions(g *graph.HierarchicalDigraph, config *PrintConfig) [
 		"  start=0", // Needed for placement determinism.
 	}
 
-	if config.Annotate {
+	splines := "ortho" // By far the most readable form of splines on larger graphs but incompatible with annotations.
+	if config.Annotate || config.Style.edgeWeights() {
+		splines = ""
+	}
+	if config.Style != nil && config.Style.Splines != "" {
+		splines = config.Style.Splines
+	}
+	// Unfortunately we cannot use the "concentrate" option with 'ortho' splines as it leads to segfaults on large graphs.
+	if config.Annotate && splines != "ortho" {
 		globalOptions = append(globalOptions, "  concentrate=true")
-	} else {
-		// Unfortunately we cannot use the "concentrate" option with 'ortho' splines as it leads to segfaults on large graphs.
-		globalOptions = append(
-			globalOptions,
-			"  splines=ortho", // By far the most readable form of splines on larger graphs but incompatible with annotations.
-		)
+	}
+	if splines != "" {
+		globalOptions = append(globalOptions, "  splines="+splines)
 	}
 
 	if config.Style != nil {
+		if config.Style.hasRankConstraints() {
+			globalOptions = append(globalOptions, "  newrank=true") // Needed for rank constraints on clustered nodes.
+		}
+		if config.Style.Direction != "" {
+			globalOptions = append(globalOptions, "  rankdir="+config.Style.Direction)
+		}
+		if config.Style.Font != "" {
+			globalOptions = append(
+				globalOptions,
+				fmt.Sprintf("  fontname=%q", config.Style.Font),
+				fmt.Sprintf("  node [fontname=%q]", config.Style.Font),
+				fmt.Sprintf("  edge [fontname=%q]", config.Style.Font),
+			)
+		}
 		if config.Style.Cluster > Off {
 			globalOptions = append(
 				globalOptions,
@@ -127,7 +388,9 @@ func determineGlobalOptions(g *graph.HierarchicalDigraph, config *PrintConfig) [
 				"  compound=true", // Needed for edges targeted at subgraphs.
 			)
 		}
-		if config.Style.ScaleNodes {
+		if config.Style.RankSep > 0 {
+			globalOptions = append(globalOptions, fmt.Sprintf("  ranksep=%.2f", config.Style.RankSep))
+		} else if config.Style.ScaleNodes != ScaleOff {
 			rankSep := math.Log10(float64(g.GetLevel(int(config.Granularity)).Len())) - 1
 			if rankSep < 0.3 {
 				rankSep = 0.3
@@ -139,73 +402,84 @@ func determineGlobalOptions(g *graph.HierarchicalDigraph, config *PrintConfig) [
 	return globalOptions
 }
 
-func printClusterToDot(cluster *graphCluster, config *PrintConfig) string {
+// printParentGroupToDot prints the clusters of nodes sharing the same parent inside a subgraph that
+// is labelled with the parent's name and coloured like the parent.
+func printParentGroupToDot(d *dotWriter, group *parentGroup, config *PrintConfig) {
+	d.line("  subgraph ", group.name(), " {")
+	d.indent = "  "
+	for _, cluster := range group.clusters {
+		printClusterToDot(d, cluster, config)
+	}
+	d.indent = ""
+
+	// The attributes are set after the nested clusters as they would otherwise be inherited by them.
+	d.line("    label=\"", group.parent.Name(), "\"")
+	d.line("    penwidth=2")
+	if c, ok := parseDotAttributes(config.nodeAttributes(group.parent, false, depgraph.HighlightNone))["fillcolor"]; ok {
+		d.line("    color=\"", c, "\"")
+	}
+	d.line("  }")
+}
+
+func printClusterToDot(d *dotWriter, cluster *graphCluster, config *PrintConfig) {
 	if len(cluster.members) == 0 {
 		config.Log.Warn("Found an empty node cluster associated with.", zap.String("cluster", cluster.name()), zap.String("hash", cluster.hash))
-		return ""
+		return
 	} else if len(cluster.members) == 1 {
-		return printNodeToDot(config, cluster.members[0])
+		d.line(printNodeToDot(config, cluster.members[0]))
+		return
 	}
 
-	dot := "  subgraph " + cluster.name() + "{\n"
+	d.line("  subgraph ", cluster.name(), "{")
 	for _, node := range cluster.members {
-		dot += "  " + printNodeToDot(config, node) + "\n"
+		d.line("  ", printNodeToDot(config, node))
 	}
 
 	// Print invisible nodes and edges that help node placement by forcing a grid layout.
-	dot += "    // The nodes and edges part of this subgraph defined below are only used to\n"
-	dot += "    // improve node placement but do not reflect actual dependencies.\n"
-	dot += "    node [style=invis]\n"
-	dot += "    edge [style=invis,minlen=1]\n"
-	dot += "    graph [color=blue]\n" //nolint:misspell
+	d.line("    // The nodes and edges part of this subgraph defined below are only used to")
+	d.line("    // improve node placement but do not reflect actual dependencies.")
+	d.line("    node [style=invis]")
+	d.line("    edge [style=invis,minlen=1]")
+	d.line("    graph [color=blue]") //nolint:misspell
 
 	rowSize := cluster.getWidth()
 	firstRowSize := len(cluster.members) % rowSize
 	firstRowOffset := (rowSize - firstRowSize) / 2
 	if firstRowSize > 0 {
 		for idx := 0; idx < firstRowOffset; idx++ {
-			dot += fmt.Sprintf("    \"%s_%d\"\n", cluster.name(), idx)
-			dot += fmt.Sprintf("    \"%s_%d\" -> \"%s\"\n", cluster.name(), idx, cluster.members[idx+firstRowSize].Name())
+			d.linef("    \"%s_%d\"", cluster.name(), idx)
+			d.linef("    \"%s_%d\" -> \"%s\"", cluster.name(), idx, cluster.members[idx+firstRowSize].Name())
 		}
 		for idx := firstRowOffset + firstRowSize; idx < rowSize; idx++ {
-			dot += fmt.Sprintf("    \"%s_%d\"\n", cluster.name(), idx)
-			dot += fmt.Sprintf("    \"%s_%d\" -> \"%s\"\n", cluster.name(), idx, cluster.members[idx+firstRowSize].Name())
+			d.linef("    \"%s_%d\"", cluster.name(), idx)
+			d.linef("    \"%s_%d\" -> \"%s\"", cluster.name(), idx, cluster.members[idx+firstRowSize].Name())
 		}
 	}
 	for idx := 0; idx < firstRowSize; idx++ {
-		dot += fmt.Sprintf("    \"%s\" -> \"%s\"\n", cluster.members[idx].Name(), cluster.members[idx+firstRowOffset+firstRowSize].Name())
+		d.linef("    \"%s\" -> \"%s\"", cluster.members[idx].Name(), cluster.members[idx+firstRowOffset+firstRowSize].Name())
 	}
 	for idx := firstRowSize; idx < len(cluster.members); idx++ {
 		if idx+rowSize < len(cluster.members) {
-			dot += fmt.Sprintf("   \"%s\" -> \"%s\"\n", cluster.members[idx].Name(), cluster.members[idx+rowSize].Name())
+			d.linef("   \"%s\" -> \"%s\"", cluster.members[idx].Name(), cluster.members[idx+rowSize].Name())
 		}
 	}
-	return dot + "  }"
+	d.line("  }")
 }
 
 type annotated interface {
-	NodeAttributes(annotate bool) []string
-	EdgeAttributes(target graph.Node, annotate bool) []string
+	NodeAttributes(annotate bool, highlight depgraph.Highlight) []string
+	EdgeAttributes(target graph.Node, annotate bool, weights bool, highlight depgraph.Highlight) []string
 }
 
 var (
 	_ annotated = &depgraph.Module{}
 	_ annotated = &depgraph.Package{}
+	_ annotated = &depgraph.Collapsed{}
 )
 
 func printNodeToDot(config *PrintConfig, node graph.Node) string {
-	var nodeOptions []string
-	if config.Style != nil && config.Style.ScaleNodes {
-		scaling := math.Log2(float64(node.Predecessors().Len()+node.Successors().Len())) / 5
-		if scaling < 0.1 {
-			scaling = 0.1
-		}
-		nodeOptions = append(nodeOptions, fmt.Sprintf("width=%.2f,height=%.2f", 5*scaling, scaling))
-	}
-
-	if a, ok := node.(annotated); ok {
-		nodeOptions = append(nodeOptions, a.NodeAttributes(config.Annotate)...)
-	}
+	nodeOptions := config.nodeSizeAttributes(node)
+	nodeOptions = append(nodeOptions, config.nodeAttributes(node, config.Annotate, config.nodeHighlight(node))...)
 
 	dot := "  \"" + node.Name() + "\""
 	if len(nodeOptions) > 0 {
@@ -214,10 +488,9 @@ func printNodeToDot(config *PrintConfig, node graph.Node) string {
 	return dot
 }
 
-func printEdgesToDot(config *PrintConfig, node graph.Node, clusters *graphClusters) []string {
+func printEdgesToDot(d *dotWriter, config *PrintConfig, node graph.Node, clusters *graphClusters) {
 	clustersReached := map[int]struct{}{}
 
-	var dots []string
 	for _, dep := range node.Successors().List() {
 		cluster, ok := clusters.clusterMap[dep.Hash()]
 		if !ok {
@@ -234,22 +507,21 @@ func printEdgesToDot(config *PrintConfig, node graph.Node, clusters *graphCluste
 			edgeAnnotations = append(edgeAnnotations, fmt.Sprintf("minlen=%d", minLength))
 		}
 
-		annotate := config.Annotate
+		annotate, weights := config.Annotate, config.Style.edgeWeights()
 		if len(cluster.members) > 1 {
-			annotate = false
+			annotate, weights = false, false
 			target = cluster.getRepresentative()
 			edgeAnnotations = append(edgeAnnotations, "lhead=\""+cluster.name()+"\"")
 		}
 
 		if a, ok := node.(annotated); ok {
-			edgeAnnotations = append(edgeAnnotations, a.EdgeAttributes(dep, annotate)...)
+			edgeAnnotations = append(edgeAnnotations, a.EdgeAttributes(dep, annotate, weights, config.edgeHighlight(node, dep))...)
 		}
 
-		dot := "  \"" + node.Name() + "\" -> \"" + target + "\""
 		if len(edgeAnnotations) > 0 {
-			dot += " [" + strings.Join(edgeAnnotations, ",") + "]"
+			d.line("  \"", node.Name(), "\" -> \"", target, "\" [", strings.Join(edgeAnnotations, ","), "]")
+		} else {
+			d.line("  \"", node.Name(), "\" -> \"", target, "\"")
 		}
-		dots = append(dots, dot)
 	}
-	return dots
 }
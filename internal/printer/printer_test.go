@@ -0,0 +1,720 @@
+package printer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Helcaraxan/gomod/internal/depgraph"
+	"github.com/Helcaraxan/gomod/internal/graph"
+	"github.com/Helcaraxan/gomod/internal/logger"
+	"github.com/Helcaraxan/gomod/internal/modules"
+	"github.com/Helcaraxan/gomod/internal/testutil"
+)
+
+// clusterTestGraph returns a graph where a single package imports two packages of one module and
+// one package of another module.
+func clusterTestGraph(t *testing.T) *graph.HierarchicalDigraph {
+	g := graph.NewHierarchicalDigraph(testutil.TestLogger(t).Domain(logger.GraphDomain))
+	a := depgraph.NewModule(&modules.ModuleInfo{Main: true, Path: "example.com/a"})
+	b := depgraph.NewModule(&modules.ModuleInfo{Path: "example.com/b", Version: "v1.0.0"})
+	c := depgraph.NewModule(&modules.ModuleInfo{Path: "example.com/c", Version: "v1.0.0"})
+	for _, n := range []graph.Node{a, b, c} {
+		require.NoError(t, g.AddNode(n))
+	}
+
+	cmd := depgraph.NewPackage(&modules.PackageInfo{ImportPath: "example.com/a/cmd"}, a)
+	require.NoError(t, g.AddNode(cmd))
+	for _, dep := range []graph.Node{
+		depgraph.NewPackage(&modules.PackageInfo{ImportPath: "example.com/b/x"}, b),
+		depgraph.NewPackage(&modules.PackageInfo{ImportPath: "example.com/b/y"}, b),
+		depgraph.NewPackage(&modules.PackageInfo{ImportPath: "example.com/c/z"}, c),
+	} {
+		require.NoError(t, g.AddNode(dep))
+		require.NoError(t, g.AddEdge(cmd, dep))
+	}
+	return g
+}
+
+func TestPrintParentClusters(t *testing.T) {
+	testcases := map[string]struct {
+		format   Format
+		style    *StyleOptions
+		expected string
+	}{
+		"DOT": {
+			format: FormatDOT,
+			style:  &StyleOptions{Cluster: Parent},
+			expected: `strict digraph {
+  node [shape=box,style="rounded,filled"]
+  start=0
+  splines=ortho
+  graph [style=rounded]
+  compound=true
+  subgraph cluster_parent_example_com_a {
+    "example.com/a/cmd" [fontcolor="0.000 0.000 0.000",fillcolor="0.776 0.245 1.000"]
+    label="example.com/a"
+    penwidth=2
+    color="0.776 0.245 1.000"
+  }
+  subgraph cluster_parent_example_com_b {
+    "example.com/b/x" [fontcolor="0.000 0.000 0.000",fillcolor="0.553 0.289 1.000"]
+    "example.com/b/y" [fontcolor="0.000 0.000 0.000",fillcolor="0.553 0.289 1.000"]
+    label="example.com/b"
+    penwidth=2
+    color="0.553 0.289 1.000"
+  }
+  subgraph cluster_parent_example_com_c {
+    "example.com/c/z" [fontcolor="0.000 0.000 0.000",fillcolor="0.122 0.376 1.000"]
+    label="example.com/c"
+    penwidth=2
+    color="0.122 0.376 1.000"
+  }
+  "example.com/a/cmd" -> "example.com/b/x"
+  "example.com/a/cmd" -> "example.com/b/y"
+  "example.com/a/cmd" -> "example.com/c/z"
+}
+`,
+		},
+		"DOTFull": {
+			format: FormatDOT,
+			style:  &StyleOptions{Cluster: Full},
+			expected: `strict digraph {
+  node [shape=box,style="rounded,filled"]
+  start=0
+  splines=ortho
+  graph [style=rounded]
+  compound=true
+  "example.com/a/cmd" [fontcolor="0.000 0.000 0.000",fillcolor="0.776 0.245 1.000"]
+  subgraph cluster_example_com_a_cmd{
+    "example.com/b/x" [fontcolor="0.000 0.000 0.000",fillcolor="0.553 0.289 1.000"]
+    "example.com/b/y" [fontcolor="0.000 0.000 0.000",fillcolor="0.553 0.289 1.000"]
+    "example.com/c/z" [fontcolor="0.000 0.000 0.000",fillcolor="0.122 0.376 1.000"]
+    // The nodes and edges part of this subgraph defined below are only used to
+    // improve node placement but do not reflect actual dependencies.
+    node [style=invis]
+    edge [style=invis,minlen=1]
+    graph [color=blue]
+   "example.com/b/x" -> "example.com/b/y"
+   "example.com/b/y" -> "example.com/c/z"
+  }
+  "example.com/a/cmd" -> "example.com/b/x" [minlen=4,lhead="cluster_example_com_a_cmd"]
+}
+`,
+		},
+		"DOTWithFull": {
+			format: FormatDOT,
+			style:  &StyleOptions{Cluster: Full, ClusterParents: true},
+			expected: `strict digraph {
+  node [shape=box,style="rounded,filled"]
+  start=0
+  splines=ortho
+  graph [style=rounded]
+  compound=true
+  subgraph cluster_parent_example_com_a {
+    "example.com/a/cmd" [fontcolor="0.000 0.000 0.000",fillcolor="0.776 0.245 1.000"]
+    label="example.com/a"
+    penwidth=2
+    color="0.776 0.245 1.000"
+  }
+  subgraph cluster_parent_example_com_b {
+    subgraph cluster_example_com_b_in_example_com_a_cmd{
+      "example.com/b/x" [fontcolor="0.000 0.000 0.000",fillcolor="0.553 0.289 1.000"]
+      "example.com/b/y" [fontcolor="0.000 0.000 0.000",fillcolor="0.553 0.289 1.000"]
+      // The nodes and edges part of this subgraph defined below are only used to
+      // improve node placement but do not reflect actual dependencies.
+      node [style=invis]
+      edge [style=invis,minlen=1]
+      graph [color=blue]
+     "example.com/b/x" -> "example.com/b/y"
+    }
+    label="example.com/b"
+    penwidth=2
+    color="0.553 0.289 1.000"
+  }
+  subgraph cluster_parent_example_com_c {
+    "example.com/c/z" [fontcolor="0.000 0.000 0.000",fillcolor="0.122 0.376 1.000"]
+    label="example.com/c"
+    penwidth=2
+    color="0.122 0.376 1.000"
+  }
+  "example.com/a/cmd" -> "example.com/b/x" [minlen=3,lhead="cluster_example_com_b_in_example_com_a_cmd"]
+  "example.com/a/cmd" -> "example.com/c/z"
+}
+`,
+		},
+		"Mermaid": {
+			format: FormatMermaid,
+			style:  &StyleOptions{Cluster: Parent},
+			expected: `flowchart TB
+  subgraph p0 ["example.com/a"]
+    n0("example.com/a/cmd")
+  end
+  subgraph p1 ["example.com/b"]
+    n1("example.com/b/x")
+    n2("example.com/b/y")
+  end
+  subgraph p2 ["example.com/c"]
+    n3("example.com/c/z")
+  end
+  n0 --> n1
+  n0 --> n2
+  n0 --> n3
+  classDef s0 fill:#eac1ff,color:#000000
+  class n0 s0
+  classDef s1 fill:#b5e8ff,color:#000000
+  class n1,n2 s1
+  classDef s2 fill:#ffe59f,color:#000000
+  class n3 s2
+  style p0 stroke:#eac1ff,stroke-width:2px
+  style p1 stroke:#b5e8ff,stroke-width:2px
+  style p2 stroke:#ffe59f,stroke-width:2px
+`,
+		},
+	}
+
+	for name := range testcases {
+		testcase := testcases[name]
+		t.Run(name, func(t *testing.T) {
+			outputPath := filepath.Join(t.TempDir(), "graph")
+			require.NoError(t, Print(clusterTestGraph(t), &PrintConfig{
+				Log:         testutil.TestLogger(t).Domain(logger.PrinterDomain),
+				Granularity: LevelPackages,
+				Format:      testcase.format,
+				Style:       testcase.style,
+				OutputPath:  outputPath,
+			}))
+
+			actual, err := ioutil.ReadFile(outputPath)
+			require.NoError(t, err)
+			assert.Equal(t, testcase.expected, string(actual))
+		})
+	}
+}
+
+func TestPrintTheme(t *testing.T) {
+	yes := true
+	theme := NewTheme([]ThemeRule{
+		{Label: "older than a year", Age: &AgeCondition{Comparison: depgraph.Greater, Age: 365 * 24 * time.Hour}, Fill: "orange", Border: "red"},
+		{Label: "replaced", Replaced: &yes, Fill: "grey"},
+		{Label: "main", Pattern: "example.com/main", Fill: "#1f77b4", Text: "white"},
+	})
+	theme.now = time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	testcases := map[string]struct {
+		format   Format
+		style    *StyleOptions
+		expected string
+	}{
+		"DOT": {
+			format: FormatDOT,
+			expected: `strict digraph {
+  node [shape=box,style="rounded,filled"]
+  start=0
+  splines=ortho
+  "example.com/dep" [fontcolor="0.000 0.000 0.000",fillcolor="orange",color="red"]
+  "example.com/main" [fontcolor="white",fillcolor="#1f77b4"]
+  "example.com/main" -> "example.com/dep" [style=dashed,color=lightblue]
+}
+`,
+		},
+		"DOTLegend": {
+			format: FormatDOT,
+			style:  &StyleOptions{Legend: true},
+			expected: `strict digraph {
+  node [shape=box,style="rounded,filled"]
+  start=0
+  splines=ortho
+  "example.com/dep" [fontcolor="0.000 0.000 0.000",fillcolor="orange",color="red"]
+  "example.com/main" [fontcolor="white",fillcolor="#1f77b4"]
+  "example.com/main" -> "example.com/dep" [style=dashed,color=lightblue]
+  subgraph cluster_legend {
+    label="Legend"
+    graph [style=rounded,color=black]
+    "legend_0" [label="older than a year",fillcolor="orange",fontcolor="0.000 0.000 0.000",color="red"]
+    "legend_1" [label="main",fillcolor="#1f77b4",fontcolor="white"]
+    "legend_2" [shape=point,style=invis]
+    "legend_2_label" [shape=plaintext,style="",label="indirect dependency"]
+    "legend_2" -> "legend_2_label" [style=dashed]
+    "legend_3" [shape=point,style=invis]
+    "legend_3_label" [shape=plaintext,style="",label="test-only dependency"]
+    "legend_3" -> "legend_3_label" [color=lightblue]
+  }
+}
+`,
+		},
+		"MermaidLegend": {
+			format: FormatMermaid,
+			style:  &StyleOptions{Legend: true},
+			expected: `flowchart TB
+  n0("example.com/dep")
+  n1("example.com/main")
+  n1 -.-> n0
+  subgraph legend ["Legend"]
+    l0("older than a year")
+    l1("main")
+    l2[" "] -.-> l2t["indirect dependency"]
+    l3[" "] --> l3t["test-only dependency"]
+  end
+  classDef s0 fill:orange,color:#000000,stroke:red
+  class n0,l0 s0
+  classDef s1 fill:#1f77b4,color:white
+  class n1,l1 s1
+  classDef s2 fill:none,stroke:none
+  class l2,l2t,l3,l3t s2
+  linkStyle 0,2 stroke:lightblue
+`,
+		},
+	}
+
+	for name := range testcases {
+		testcase := testcases[name]
+		t.Run(name, func(t *testing.T) {
+			outputPath := filepath.Join(t.TempDir(), "graph")
+			require.NoError(t, Print(dataTestGraph(t), &PrintConfig{
+				Log:         testutil.TestLogger(t).Domain(logger.PrinterDomain),
+				Granularity: LevelModules,
+				Format:      testcase.format,
+				Style:       testcase.style,
+				Theme:       theme,
+				OutputPath:  outputPath,
+			}))
+
+			actual, err := ioutil.ReadFile(outputPath)
+			require.NoError(t, err)
+			assert.Equal(t, testcase.expected, string(actual))
+		})
+	}
+}
+
+func TestPrintColourBy(t *testing.T) {
+	testcases := map[string]struct {
+		style    *StyleOptions
+		theme    *Theme
+		expected string
+	}{
+		"Age": {
+			style: &StyleOptions{ColourBy: ColourByAge, Legend: true},
+			expected: `strict digraph {
+  node [shape=box,style="rounded,filled"]
+  start=0
+  splines=ortho
+  "example.com/dep" [fontcolor="0.000 0.000 0.000",fillcolor="0.333 0.300 1.000"]
+  "example.com/main" [fontcolor="0.000 0.000 0.000",fillcolor="0.000 0.000 0.850"]
+  "example.com/main" -> "example.com/dep" [style=dashed,color=lightblue]
+  subgraph cluster_legend {
+    label="Legend"
+    graph [style=rounded,color=black]
+    "legend_0" [label="version from 2020-06-15",fillcolor="0.333 0.700 1.000",fontcolor="0.000 0.000 0.000"]
+    "legend_1" [label="unknown age",fillcolor="0.000 0.000 0.850",fontcolor="0.000 0.000 0.000"]
+    "legend_2" [label="test-only module",fillcolor="0.333 0.300 1.000",fontcolor="0.000 0.000 0.000"]
+    "legend_3" [shape=point,style=invis]
+    "legend_3_label" [shape=plaintext,style="",label="indirect dependency"]
+    "legend_3" -> "legend_3_label" [style=dashed]
+    "legend_4" [shape=point,style=invis]
+    "legend_4_label" [shape=plaintext,style="",label="test-only dependency"]
+    "legend_4" -> "legend_4_label" [color=lightblue]
+  }
+}
+`,
+		},
+		"Rdeps": {
+			style: &StyleOptions{ColourBy: ColourByRdeps, Legend: true},
+			expected: `strict digraph {
+  node [shape=box,style="rounded,filled"]
+  start=0
+  splines=ortho
+  "example.com/dep" [fontcolor="0.000 0.000 0.000",fillcolor="0.000 0.300 1.000"]
+  "example.com/main" [fontcolor="0.000 0.000 0.000",fillcolor="0.333 0.300 1.000"]
+  "example.com/main" -> "example.com/dep" [style=dashed,color=lightblue]
+  subgraph cluster_legend {
+    label="Legend"
+    graph [style=rounded,color=black]
+    "legend_0" [label="0 dependents",fillcolor="0.333 0.700 1.000",fontcolor="0.000 0.000 0.000"]
+    "legend_1" [label="1 dependent",fillcolor="0.000 0.700 1.000",fontcolor="0.000 0.000 0.000"]
+    "legend_2" [label="test-only module",fillcolor="0.000 0.300 1.000",fontcolor="0.000 0.000 0.000"]
+    "legend_3" [shape=point,style=invis]
+    "legend_3_label" [shape=plaintext,style="",label="indirect dependency"]
+    "legend_3" -> "legend_3_label" [style=dashed]
+    "legend_4" [shape=point,style=invis]
+    "legend_4_label" [shape=plaintext,style="",label="test-only dependency"]
+    "legend_4" -> "legend_4_label" [color=lightblue]
+  }
+}
+`,
+		},
+		"RdepsWithTheme": {
+			style: &StyleOptions{ColourBy: ColourByRdeps},
+			theme: NewTheme([]ThemeRule{{Label: "main", Pattern: "example.com/main", Fill: "#1f77b4", Text: "white"}}),
+			expected: `strict digraph {
+  node [shape=box,style="rounded,filled"]
+  start=0
+  splines=ortho
+  "example.com/dep" [fontcolor="0.000 0.000 0.000",fillcolor="0.000 0.300 1.000"]
+  "example.com/main" [fontcolor="white",fillcolor="#1f77b4"]
+  "example.com/main" -> "example.com/dep" [style=dashed,color=lightblue]
+}
+`,
+		},
+	}
+
+	for name := range testcases {
+		testcase := testcases[name]
+		t.Run(name, func(t *testing.T) {
+			outputPath := filepath.Join(t.TempDir(), "graph")
+			require.NoError(t, Print(dataTestGraph(t), &PrintConfig{
+				Log:         testutil.TestLogger(t).Domain(logger.PrinterDomain),
+				Granularity: LevelModules,
+				Style:       testcase.style,
+				Theme:       testcase.theme,
+				OutputPath:  outputPath,
+			}))
+
+			actual, err := ioutil.ReadFile(outputPath)
+			require.NoError(t, err)
+			assert.Equal(t, testcase.expected, string(actual))
+		})
+	}
+}
+
+func TestPrintLayout(t *testing.T) {
+	style := &StyleOptions{
+		Direction: "LR",
+		RankSep:   1.2,
+		Splines:   "polyline",
+		Font:      "Fira Sans",
+		NodeOverrides: []NodeOverride{
+			{Pattern: "example.com/main", Attributes: map[string]string{"shape": "ellipse", "fillcolor": "white"}},
+		},
+	}
+
+	testcases := map[string]struct {
+		format   Format
+		annotate bool
+		weights  bool
+		expected string
+	}{
+		"DOT": {
+			format: FormatDOT,
+			expected: `strict digraph {
+  node [shape=box,style="rounded,filled"]
+  start=0
+  splines=polyline
+  rankdir=LR
+  fontname="Fira Sans"
+  node [fontname="Fira Sans"]
+  edge [fontname="Fira Sans"]
+  ranksep=1.20
+  "example.com/dep" [fontcolor="0.000 0.000 0.000",fillcolor="0.620 0.276 1.000"]
+  "example.com/main" [fontcolor="0.000 0.000 0.000",fillcolor="white",shape="ellipse"]
+  "example.com/main" -> "example.com/dep" [style=dashed,color=lightblue]
+}
+`,
+		},
+		"DOTAnnotated": {
+			format:   FormatDOT,
+			annotate: true,
+			expected: `strict digraph {
+  node [shape=box,style="rounded,filled"]
+  start=0
+  concentrate=true
+  splines=polyline
+  rankdir=LR
+  fontname="Fira Sans"
+  node [fontname="Fira Sans"]
+  edge [fontname="Fira Sans"]
+  ranksep=1.20
+  "example.com/dep" [fontcolor="0.000 0.000 0.000",fillcolor="0.620 0.276 1.000",label=<example.com/dep<br /><font point-size="10">example.com/fork<br />v1.1.0</font>>]
+  "example.com/main" [fontcolor="0.000 0.000 0.000",fillcolor="white",shape="ellipse"]
+  "example.com/main" -> "example.com/dep" [style=dashed,color=lightblue,label=<<font point-size="10">v1.0.0</font>>]
+}
+`,
+		},
+		"DOTAnnotatedWeights": {
+			format:   FormatDOT,
+			annotate: true,
+			weights:  true,
+			expected: `strict digraph {
+  node [shape=box,style="rounded,filled"]
+  start=0
+  concentrate=true
+  splines=polyline
+  rankdir=LR
+  fontname="Fira Sans"
+  node [fontname="Fira Sans"]
+  edge [fontname="Fira Sans"]
+  ranksep=1.20
+  "example.com/dep" [fontcolor="0.000 0.000 0.000",fillcolor="0.620 0.276 1.000",label=<example.com/dep<br /><font point-size="10">example.com/fork<br />v1.1.0</font>>]
+  "example.com/main" [fontcolor="0.000 0.000 0.000",fillcolor="white",shape="ellipse"]
+  "example.com/main" -> "example.com/dep" [style=dashed,color=lightblue,label=<<font point-size="10">v1.0.0<br />1 import</font>>]
+}
+`,
+		},
+		"DOTWeights": {
+			format:  FormatDOT,
+			weights: true,
+			expected: `strict digraph {
+  node [shape=box,style="rounded,filled"]
+  start=0
+  splines=polyline
+  rankdir=LR
+  fontname="Fira Sans"
+  node [fontname="Fira Sans"]
+  edge [fontname="Fira Sans"]
+  ranksep=1.20
+  "example.com/dep" [fontcolor="0.000 0.000 0.000",fillcolor="0.620 0.276 1.000"]
+  "example.com/main" [fontcolor="0.000 0.000 0.000",fillcolor="white",shape="ellipse"]
+  "example.com/main" -> "example.com/dep" [style=dashed,color=lightblue,label=<<font point-size="10">1 import</font>>]
+}
+`,
+		},
+		"Mermaid": {
+			format: FormatMermaid,
+			expected: `flowchart LR
+  n0("example.com/dep")
+  n1("example.com/main")
+  n1 -.-> n0
+  classDef s0 fill:#b9ccff,color:#000000
+  class n0 s0
+  classDef s1 fill:white,color:#000000
+  class n1 s1
+  linkStyle 0 stroke:lightblue
+`,
+		},
+	}
+
+	for name := range testcases {
+		testcase := testcases[name]
+		t.Run(name, func(t *testing.T) {
+			style := *style
+			style.EdgeWeights = testcase.weights
+
+			outputPath := filepath.Join(t.TempDir(), "graph")
+			require.NoError(t, Print(dataTestGraph(t), &PrintConfig{
+				Log:         testutil.TestLogger(t).Domain(logger.PrinterDomain),
+				Granularity: LevelModules,
+				Annotate:    testcase.annotate,
+				Format:      testcase.format,
+				Style:       &style,
+				OutputPath:  outputPath,
+			}))
+
+			actual, err := ioutil.ReadFile(outputPath)
+			require.NoError(t, err)
+			assert.Equal(t, testcase.expected, string(actual))
+		})
+	}
+}
+
+func TestPrintRanks(t *testing.T) {
+	testcases := map[string]struct {
+		style    *StyleOptions
+		expected string
+	}{
+		"Constraints": {
+			style: &StyleOptions{PinMain: true, SameRank: []string{"example.com/{b,d}", "example.com/none"}},
+			expected: `strict digraph {
+  node [shape=box,style="rounded,filled"]
+  start=0
+  splines=ortho
+  newrank=true
+  "example.com/b" [fontcolor="0.000 0.000 0.000",fillcolor="0.553 0.289 1.000"]
+  "example.com/c" [fontcolor="0.000 0.000 0.000",fillcolor="0.122 0.376 1.000"]
+  "example.com/d" [fontcolor="0.000 0.000 0.000",fillcolor="0.259 0.348 1.000"]
+  "example.com/e" [fontcolor="0.000 0.000 0.000",fillcolor="0.659 0.268 1.000"]
+  "example.com/f" [fontcolor="0.000 0.000 0.000",fillcolor="0.616 0.277 1.000"]
+  "example.com/main" [fontcolor="0.000 0.000 0.000",fillcolor="0.133 0.373 1.000"]
+  "example.com/b" -> "example.com/c" [color=lightblue]
+  "example.com/e" -> "example.com/f" [color=lightblue]
+  "example.com/f" -> "example.com/e" [color=lightblue]
+  "example.com/main" -> "example.com/b" [color=lightblue]
+  "example.com/main" -> "example.com/d" [color=lightblue]
+  {rank=source; "example.com/main"}
+  {rank=same; "example.com/b"; "example.com/d"}
+}
+`,
+		},
+		"MaxRank": {
+			style: &StyleOptions{MaxRank: 1},
+			expected: `strict digraph {
+  node [shape=box,style="rounded,filled"]
+  start=0
+  splines=ortho
+  "example.com/b" [fontcolor="0.000 0.000 0.000",fillcolor="0.553 0.289 1.000"]
+  "example.com/d" [fontcolor="0.000 0.000 0.000",fillcolor="0.259 0.348 1.000"]
+  "example.com/e" [fontcolor="0.000 0.000 0.000",fillcolor="0.659 0.268 1.000"]
+  "example.com/f" [fontcolor="0.000 0.000 0.000",fillcolor="0.616 0.277 1.000"]
+  "example.com/main" [fontcolor="0.000 0.000 0.000",fillcolor="0.133 0.373 1.000"]
+  "example.com/e" -> "example.com/f" [color=lightblue]
+  "example.com/f" -> "example.com/e" [color=lightblue]
+  "example.com/main" -> "example.com/b" [color=lightblue]
+  "example.com/main" -> "example.com/d" [color=lightblue]
+}
+`,
+		},
+	}
+
+	for name := range testcases {
+		testcase := testcases[name]
+		t.Run(name, func(t *testing.T) {
+			g := graph.NewHierarchicalDigraph(testutil.TestLogger(t).Domain(logger.GraphDomain))
+			nodes := map[string]*depgraph.Module{}
+			for _, name := range []string{"main", "b", "c", "d", "e", "f"} {
+				nodes[name] = depgraph.NewModule(&modules.ModuleInfo{Main: name == "main", Path: "example.com/" + name})
+				require.NoError(t, g.AddNode(nodes[name]))
+			}
+			// The cycle between 'e' and 'f' can not be reached from the main module.
+			for _, edge := range [][2]string{{"main", "b"}, {"b", "c"}, {"main", "d"}, {"e", "f"}, {"f", "e"}} {
+				require.NoError(t, g.AddEdge(nodes[edge[0]], nodes[edge[1]]))
+			}
+
+			outputPath := filepath.Join(t.TempDir(), "graph")
+			require.NoError(t, Print(g, &PrintConfig{
+				Log:         testutil.TestLogger(t).Domain(logger.PrinterDomain),
+				Granularity: LevelModules,
+				Style:       testcase.style,
+				OutputPath:  outputPath,
+			}))
+
+			actual, err := ioutil.ReadFile(outputPath)
+			require.NoError(t, err)
+			assert.Equal(t, testcase.expected, string(actual))
+		})
+	}
+}
+
+func TestPrintScaleByLines(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "x.go"), []byte("package x\n\nfunc X() {}\n"), 0o600))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "y.go"), []byte("package x\n"), 0o600))
+
+	g := graph.NewHierarchicalDigraph(testutil.TestLogger(t).Domain(logger.GraphDomain))
+	main := depgraph.NewModule(&modules.ModuleInfo{Main: true, Path: "example.com/main"})
+	dep := depgraph.NewModule(&modules.ModuleInfo{Path: "example.com/dep", Version: "v1.0.0"})
+	missing := depgraph.NewModule(&modules.ModuleInfo{Path: "example.com/missing", Version: "v1.0.0"})
+	cmd := depgraph.NewPackage(&modules.PackageInfo{ImportPath: "example.com/main/cmd"}, main)
+	x := depgraph.NewPackage(&modules.PackageInfo{ImportPath: "example.com/dep/x", Dir: dir, GoFiles: []string{"x.go", "y.go"}}, dep)
+	y := depgraph.NewPackage(&modules.PackageInfo{ImportPath: "example.com/missing/y", Dir: dir, GoFiles: []string{"z.go"}}, missing)
+	for _, n := range []graph.Node{main, dep, missing, cmd, x, y} {
+		require.NoError(t, g.AddNode(n))
+	}
+	require.NoError(t, g.AddEdge(cmd, x))
+	require.NoError(t, g.AddEdge(cmd, y))
+
+	outputPath := filepath.Join(t.TempDir(), "graph.dot")
+	require.NoError(t, Print(g, &PrintConfig{
+		Log:         testutil.TestLogger(t).Domain(logger.PrinterDomain),
+		Granularity: LevelModules,
+		Style:       &StyleOptions{ScaleNodes: ScaleByLines},
+		OutputPath:  outputPath,
+	}))
+
+	// Modules without any lines of code, or whose files can not be read, have the minimum size.
+	actual, err := ioutil.ReadFile(outputPath)
+	require.NoError(t, err)
+	assert.Equal(t, `strict digraph {
+  node [shape=box,style="rounded,filled"]
+  start=0
+  splines=ortho
+  ranksep=0.30
+  "example.com/dep" [width=5.00,height=1.00,fontcolor="0.000 0.000 0.000",fillcolor="0.620 0.276 1.000"]
+  "example.com/main" [width=0.50,height=0.10,fontcolor="0.000 0.000 0.000",fillcolor="0.133 0.373 1.000"]
+  "example.com/missing" [width=0.50,height=0.10,fontcolor="0.000 0.000 0.000",fillcolor="0.506 0.299 1.000"]
+  "example.com/main" -> "example.com/dep" [color=lightblue]
+  "example.com/main" -> "example.com/missing" [color=lightblue]
+}
+`, string(actual))
+
+	outputPath = filepath.Join(t.TempDir(), "graph.json")
+	require.NoError(t, Print(g, &PrintConfig{
+		Log:         testutil.TestLogger(t).Domain(logger.PrinterDomain),
+		Granularity: LevelModules,
+		Format:      FormatJSON,
+		Style:       &StyleOptions{ScaleNodes: ScaleByLines},
+		OutputPath:  outputPath,
+	}))
+
+	raw, err := ioutil.ReadFile(outputPath)
+	require.NoError(t, err)
+	var doc jsonDocument
+	require.NoError(t, json.Unmarshal(raw, &doc))
+	lines := map[string]interface{}{}
+	for _, node := range doc.Nodes {
+		lines[node.Name] = node.Data["line_count"]
+	}
+	assert.Equal(t, map[string]interface{}{"example.com/dep": 4.0, "example.com/main": 0.0, "example.com/missing": nil}, lines)
+}
+
+// syntheticGraph returns a graph with the given number of modules, each with the same number of
+// packages. The modules form a tree in which each module has up to 'fanOut' children: each package
+// imports a package of all child modules of its own module. The children of a module thus share
+// their predecessors and end up in the same cluster when clustering is enabled.
+func syntheticGraph(b *testing.B, moduleCount int, packageCount int, fanOut int) *graph.HierarchicalDigraph {
+	g := graph.NewHierarchicalDigraph(logger.NewBuilder(discardSyncer{}).Domain(logger.GraphDomain))
+
+	packages := make([][]*depgraph.Package, moduleCount)
+	for m := range packages {
+		module := depgraph.NewModule(&modules.ModuleInfo{Main: m == 0, Path: fmt.Sprintf("example.com/m%d", m), Version: "v1.0.0"})
+		require.NoError(b, g.AddNode(module))
+		for p := 0; p < packageCount; p++ {
+			pkg := depgraph.NewPackage(&modules.PackageInfo{ImportPath: fmt.Sprintf("%s/p%d", module.Name(), p)}, module)
+			require.NoError(b, g.AddNode(pkg))
+			packages[m] = append(packages[m], pkg)
+		}
+	}
+	for m := range packages {
+		for p, pkg := range packages[m] {
+			for child := fanOut*m + 1; child <= fanOut*m+fanOut && child < moduleCount; child++ {
+				require.NoError(b, g.AddEdge(pkg, packages[child][(p+child)%packageCount]))
+			}
+		}
+	}
+	return g
+}
+
+// discardSyncer drops all log output so that logging does not affect benchmark results.
+type discardSyncer struct{}
+
+func (discardSyncer) Write(p []byte) (int, error) { return len(p), nil }
+func (discardSyncer) Sync() error                 { return nil }
+
+func BenchmarkPrintDOT(b *testing.B) {
+	binaryTree := syntheticGraph(b, 10000, 10, 2)
+	// Clustered printing computes the depth of each cluster, so a smaller graph keeps it tractable.
+	wideTree := syntheticGraph(b, 2000, 10, 20)
+	log := logger.NewBuilder(discardSyncer{}).Domain(logger.PrinterDomain)
+
+	benchmarks := map[string]struct {
+		graph       *graph.HierarchicalDigraph
+		granularity Level
+		style       *StyleOptions
+	}{
+		"Modules":             {graph: binaryTree, granularity: LevelModules},
+		"Packages":            {graph: binaryTree, granularity: LevelPackages},
+		"ModulesClusterFull":  {graph: wideTree, granularity: LevelModules, style: &StyleOptions{Cluster: Full}},
+		"PackagesClusterFull": {graph: wideTree, granularity: LevelPackages, style: &StyleOptions{Cluster: Full}},
+	}
+
+	for name := range benchmarks {
+		benchmark := benchmarks[name]
+		b.Run(name, func(b *testing.B) {
+			outputDir := b.TempDir()
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				require.NoError(b, Print(benchmark.graph, &PrintConfig{
+					Log:         log,
+					Granularity: benchmark.granularity,
+					Style:       benchmark.style,
+					OutputPath:  filepath.Join(outputDir, fmt.Sprintf("graph-%d.dot", i)),
+				}))
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package printer
+
+import (
+	"fmt"
+	"math"
+
+	"go.uber.org/zap"
+
+	"github.com/Helcaraxan/gomod/internal/depgraph"
+	"github.com/Helcaraxan/gomod/internal/graph"
+)
+
+// NodeScale is the property of nodes that determines their size in a printed graph.
+type NodeScale uint8
+
+const (
+	// Print all nodes with the same size.
+	ScaleOff NodeScale = iota
+	// Size nodes after the number of their successors and predecessors.
+	ScaleByEdges
+	// Size nodes after the number of Go files of their packages.
+	ScaleByFiles
+	// Size nodes after the number of lines of code of their packages.
+	ScaleByLines
+	// Size nodes after the number of packages of their module.
+	ScaleByPackages
+)
+
+func (s NodeScale) String() string {
+	return map[NodeScale]string{
+		ScaleOff:        "off",
+		ScaleByEdges:    "edges",
+		ScaleByFiles:    "files",
+		ScaleByLines:    "loc",
+		ScaleByPackages: "packages",
+	}[s]
+}
+
+// sizeScale holds the code footprint of the printed nodes on which their size is based. The largest
+// node is printed with the same size as a node with 32 edges when scaling by edges.
+type sizeScale struct {
+	metric NodeScale
+	values map[string]int
+	max    int
+}
+
+func newSizeScale(g *graph.HierarchicalDigraph, config *PrintConfig) *sizeScale {
+	if config.Style == nil || config.Style.ScaleNodes == ScaleOff || config.Style.ScaleNodes == ScaleByEdges {
+		return nil
+	}
+
+	s := &sizeScale{metric: config.Style.ScaleNodes, values: map[string]int{}}
+	for _, node := range g.GetLevel(int(config.Granularity)).List() {
+		value, err := footprint(s.metric, node)
+		if err != nil {
+			config.Log.Warn("Could not determine the footprint of a node.", zap.String("node", node.Name()), zap.Stringer("metric", s.metric), zap.Error(err))
+			continue
+		} else if value < 0 {
+			continue
+		}
+		if value > s.max {
+			s.max = value
+		}
+		s.values[node.Hash()] = value
+	}
+	return s
+}
+
+// footprint returns the value of the metric for the node. Modules sum the values of their packages
+// and packages take the package count of their module. A negative value is returned for nodes to
+// which the metric does not apply.
+func footprint(metric NodeScale, node graph.Node) (int, error) {
+	var pkgs []*depgraph.Package
+	switch n := node.(type) {
+	case *depgraph.Package:
+		pkgs = []*depgraph.Package{n}
+	case *depgraph.Module:
+		for _, child := range n.Children().List() {
+			pkgs = append(pkgs, child.(*depgraph.Package))
+		}
+	default:
+		return -1, nil
+	}
+
+	var total int
+	switch metric {
+	case ScaleByFiles:
+		for _, pkg := range pkgs {
+			total += pkg.FileCount()
+		}
+	case ScaleByLines:
+		for _, pkg := range pkgs {
+			lines, err := pkg.LineCount()
+			if err != nil {
+				return 0, err
+			}
+			total += lines
+		}
+	case ScaleByPackages:
+		if parent := node.Parent(); parent != nil {
+			node = parent
+		}
+		total = node.Children().Len()
+	default:
+		return -1, nil
+	}
+	return total, nil
+}
+
+// nodeSizeAttributes returns the DOT attributes that set the size of the node.
+func (config *PrintConfig) nodeSizeAttributes(node graph.Node) []string {
+	if config.Style == nil || config.Style.ScaleNodes == ScaleOff {
+		return nil
+	}
+
+	var scaling float64
+	if config.sizes == nil {
+		scaling = math.Log2(float64(node.Predecessors().Len()+node.Successors().Len())) / 5
+	} else if value, ok := config.sizes.values[node.Hash()]; ok && config.sizes.max > 0 {
+		scaling = math.Log2(float64(1+value)) / math.Log2(float64(1+config.sizes.max))
+	}
+	if scaling < 0.1 {
+		scaling = 0.1
+	}
+	return []string{fmt.Sprintf("width=%.2f,height=%.2f", 5*scaling, scaling)}
+}
+
+// legendLabel describes how the size of nodes is determined.
+func (s NodeScale) legendLabel() string {
+	return "size grows with the number of " + map[NodeScale]string{
+		ScaleByEdges:    "edges",
+		ScaleByFiles:    "Go files",
+		ScaleByLines:    "lines of code",
+		ScaleByPackages: "packages",
+	}[s]
+}
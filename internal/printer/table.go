@@ -0,0 +1,226 @@
+package printer
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/Helcaraxan/gomod/internal/graph"
+)
+
+var ErrUnknownColumn = errors.New("unknown column")
+
+type tableColumn struct {
+	name  string
+	value func(row map[string]string) string
+}
+
+// dataColumn returns a column whose values are taken as-is from the property with the given name.
+func dataColumn(name string, property string) tableColumn {
+	return tableColumn{name: name, value: func(row map[string]string) string { return row[property] }}
+}
+
+// The columns of the node and edge tables in the order in which they are printed.
+var (
+	nodeColumns = []tableColumn{
+		dataColumn("name", "name"),
+		dataColumn("level", "level"),
+		{name: "module", value: func(row map[string]string) string {
+			if m, ok := row["module"]; ok {
+				return m
+			}
+			return row["name"]
+		}},
+		dataColumn("version", "version"),
+		dataColumn("replace", "replacement"),
+		dataColumn("test", "test_only"),
+		dataColumn("indirect", "indirect"),
+		dataColumn("highlighted", "highlighted"),
+	}
+	edgeColumns = []tableColumn{
+		dataColumn("source", "source"),
+		dataColumn("target", "target"),
+		dataColumn("requested_version", "version_constraint"),
+		dataColumn("weight", "weight"),
+		dataColumn("indirect", "indirect"),
+		dataColumn("test", "test_only"),
+		dataColumn("highlighted", "highlighted"),
+	}
+)
+
+// TableColumns returns the names of all the columns that can be selected for the tabular formats.
+func TableColumns() []string {
+	var names []string
+	seen := map[string]bool{}
+	for _, column := range append(append([]tableColumn{}, nodeColumns...), edgeColumns...) {
+		if !seen[column.name] {
+			seen[column.name] = true
+			names = append(names, column.name)
+		}
+	}
+	return names
+}
+
+// columnSelection returns the names of the columns that should be printed. Without an explicit
+// selection these are all columns, except for the one indicating highlighted nodes and edges when
+// highlighting is not in effect.
+func columnSelection(config *PrintConfig) []string {
+	if len(config.Columns) > 0 {
+		return config.Columns
+	}
+	var names []string
+	for _, name := range TableColumns() {
+		if name != highlightDataKey.name || config.Highlight != nil {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// selectColumns returns the columns of a table that are part of the selection, in the table's own
+// order.
+func selectColumns(columns []tableColumn, selection []string) []tableColumn {
+	selected := map[string]bool{}
+	for _, name := range selection {
+		selected[name] = true
+	}
+	var result []tableColumn
+	for _, column := range columns {
+		if selected[column.name] {
+			result = append(result, column)
+		}
+	}
+	return result
+}
+
+// printTables writes one table listing the nodes and one listing the edges of the graph. When
+// printing to the terminal both tables are separated by an empty line. Otherwise they are written to
+// separate files whose names are derived from the output path: 'deps.csv' results in 'deps.nodes.csv'
+// and 'deps.edges.csv'. The selection of columns applies to both tables and a table for which no
+// column is selected is omitted entirely.
+func printTables(g *graph.HierarchicalDigraph, config *PrintConfig) error {
+	known := map[string]bool{}
+	for _, name := range TableColumns() {
+		known[name] = true
+	}
+	for _, name := range config.Columns {
+		if !known[name] {
+			config.Log.Error("Unknown column requested.", zap.String("column", name), zap.Strings("known", TableColumns()))
+			return fmt.Errorf("%q: %w", name, ErrUnknownColumn)
+		}
+	}
+
+	nodeTable, edgeTable, err := buildTables(g, config)
+	if err != nil {
+		config.Log.Error("Failed to generate graph.", zap.Stringer("format", config.Format), zap.Error(err))
+		return err
+	}
+
+	if config.OutputPath == "" {
+		var content []string
+		for _, table := range [][]string{nodeTable, edgeTable} {
+			if len(table) == 0 {
+				continue
+			}
+			if len(content) > 0 {
+				content = append(content, "")
+			}
+			content = append(content, table...)
+		}
+		return writeOutput(config, "", content)
+	}
+
+	ext := filepath.Ext(config.OutputPath)
+	base := strings.TrimSuffix(config.OutputPath, ext)
+	if len(nodeTable) > 0 {
+		if err = writeOutput(config, base+".nodes"+ext, nodeTable); err != nil {
+			return err
+		}
+	}
+	if len(edgeTable) > 0 {
+		if err = writeOutput(config, base+".edges"+ext, edgeTable); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func buildTables(g *graph.HierarchicalDigraph, config *PrintConfig) (nodeTable []string, edgeTable []string, err error) {
+	level := "module"
+	if config.Granularity == LevelPackages {
+		level = "package"
+	}
+
+	nodes, edges := collectGraphData(g, config)
+	names := map[string]string{}
+	var nodeRows []map[string]string
+	for _, node := range nodes {
+		names[node.id] = node.label
+		row := tableRow(node.data)
+		row["name"] = node.label
+		row["level"] = level
+		nodeRows = append(nodeRows, row)
+	}
+	var edgeRows []map[string]string
+	for _, edge := range edges {
+		row := tableRow(edge.data)
+		row["source"] = names[edge.source]
+		row["target"] = names[edge.target]
+		edgeRows = append(edgeRows, row)
+	}
+
+	selection := columnSelection(config)
+	if nodeTable, err = formatTable(config, selectColumns(nodeColumns, selection), nodeRows); err != nil {
+		return nil, nil, err
+	}
+	if edgeTable, err = formatTable(config, selectColumns(edgeColumns, selection), edgeRows); err != nil {
+		return nil, nil, err
+	}
+	return nodeTable, edgeTable, nil
+}
+
+func tableRow(values []dataValue) map[string]string {
+	row := map[string]string{}
+	for _, v := range values {
+		row[v.key.name] = v.value
+	}
+	return row
+}
+
+// formatTable renders the rows, preceded by a header, as lines of comma or tab-separated values.
+func formatTable(config *PrintConfig, columns []tableColumn, rows []map[string]string) ([]string, error) {
+	if len(columns) == 0 {
+		return nil, nil
+	}
+
+	var buf strings.Builder
+	w := csv.NewWriter(&buf)
+	if config.Format == FormatTSV {
+		w.Comma = '\t'
+	}
+
+	record := make([]string, len(columns))
+	for idx, column := range columns {
+		record[idx] = column.name
+	}
+	if err := w.Write(record); err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		for idx, column := range columns {
+			record[idx] = column.value(row)
+		}
+		if err := w.Write(record); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, err
+	}
+	return strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n"), nil
+}
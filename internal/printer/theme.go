@@ -0,0 +1,175 @@
+package printer
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bmatcuk/doublestar/v3"
+
+	"github.com/Helcaraxan/gomod/internal/depgraph"
+	"github.com/Helcaraxan/gomod/internal/graph"
+)
+
+// Theme assigns colours to the nodes matched by its rules. Nodes that are not matched by any rule
+// keep the colours derived from the hash of their module, which is the default theme.
+type Theme struct {
+	Rules []ThemeRule
+
+	// Reference time against which the age of modules is determined.
+	now time.Time
+}
+
+// NewTheme returns a theme applying the given rules in order, the first matching rule determining
+// the colours of a node.
+func NewTheme(rules []ThemeRule) *Theme {
+	return &Theme{Rules: rules, now: time.Now()}
+}
+
+// ThemeRule sets the colours of all nodes that meet each of its conditions. Conditions that are not
+// set are ignored. Colours are DOT colour values: a name, a '#rrggbb' value or an 'H S V' triplet.
+type ThemeRule struct {
+	// Description of the nodes matched by the rule when shown in a legend.
+	Label string
+
+	// Glob pattern matching the node's name or, for packages, the name of their module.
+	Pattern string
+	// Whether the node is a test-only dependency.
+	TestOnly *bool
+	// Whether the node's module is replaced.
+	Replaced *bool
+	// Age of the version of the node's module.
+	Age *AgeCondition
+
+	Fill   string
+	Text   string
+	Border string
+}
+
+// AgeCondition compares the age of a module version with a threshold.
+type AgeCondition struct {
+	Comparison depgraph.Comparison
+	Age        time.Duration
+}
+
+func (a AgeCondition) matches(age time.Duration) bool {
+	return a.Comparison.Compare(int(age), int(a.Age))
+}
+
+// match returns the first rule of the theme that applies to the node, if any.
+func (t *Theme) match(node graph.Node) (int, *ThemeRule) {
+	if t == nil {
+		return -1, nil
+	}
+	a, ok := node.(depgraph.DataAnnotated)
+	if !ok {
+		return -1, nil
+	}
+
+	data := a.NodeData()
+	for idx := range t.Rules {
+		if t.Rules[idx].matches(data, t.now) {
+			return idx, &t.Rules[idx]
+		}
+	}
+	return -1, nil
+}
+
+func (r *ThemeRule) matches(data map[string]interface{}, now time.Time) bool {
+	if r.Pattern != "" {
+		path, _ := data["path"].(string)
+		module, _ := data["module"].(string)
+		pathMatch, _ := doublestar.Match(r.Pattern, path)
+		moduleMatch, _ := doublestar.Match(r.Pattern, module)
+		if !pathMatch && !moduleMatch {
+			return false
+		}
+	}
+	if r.TestOnly != nil {
+		if testOnly, _ := data["test_only"].(bool); testOnly != *r.TestOnly {
+			return false
+		}
+	}
+	if r.Replaced != nil {
+		if _, replaced := data["replacement"]; replaced != *r.Replaced {
+			return false
+		}
+	}
+	if r.Age != nil {
+		raw, _ := data["timestamp"].(string)
+		timestamp, err := time.Parse(time.RFC3339, raw)
+		if err != nil || !r.Age.matches(now.Sub(timestamp)) {
+			return false
+		}
+	}
+	return true
+}
+
+// apply overrides the colours in the DOT attributes of a node with those of the rule. The border of
+// a highlighted node is preserved so that it remains recognisable.
+func (r *ThemeRule) apply(attributes []string, highlight depgraph.Highlight) []string {
+	overrides := map[string]string{}
+	if r.Fill != "" {
+		overrides["fillcolor"] = r.Fill
+	}
+	if r.Text != "" {
+		overrides["fontcolor"] = r.Text
+	}
+	if r.Border != "" && highlight != depgraph.Highlighted {
+		overrides["color"] = r.Border
+	}
+	return overrideAttributes(attributes, overrides)
+}
+
+// overrideAttributes sets the values of the given DOT attributes, replacing those that are already
+// present and appending the others in a deterministic order: colours first, the rest sorted by key.
+func overrideAttributes(attributes []string, overrides map[string]string) []string {
+	result := make([]string, 0, len(attributes)+len(overrides))
+	seen := map[string]bool{}
+	for _, attribute := range attributes {
+		key := attribute
+		if idx := strings.Index(attribute, "="); idx >= 0 {
+			key = attribute[:idx]
+		}
+		if value, ok := overrides[key]; ok {
+			attribute = key + "=\"" + value + "\""
+			seen[key] = true
+		}
+		result = append(result, attribute)
+	}
+	keys := []string{"fillcolor", "fontcolor", "color"}
+	var others []string
+	for key := range overrides {
+		if key != "fillcolor" && key != "fontcolor" && key != "color" {
+			others = append(others, key)
+		}
+	}
+	sort.Strings(others)
+	for _, key := range append(keys, others...) {
+		if value, ok := overrides[key]; ok && !seen[key] {
+			result = append(result, key+"=\""+value+"\"")
+		}
+	}
+	return result
+}
+
+// nodeAttributes returns the DOT attributes of an annotated node with the colours of the gradient
+// and of the theme applied, the latter taking precedence. Faded nodes keep their toned-down colours
+// so that highlighted ones stand out. Node overrides of the style options are applied last.
+func (c *PrintConfig) nodeAttributes(node graph.Node, annotate bool, highlight depgraph.Highlight) []string {
+	a, ok := node.(annotated)
+	if !ok {
+		return nil
+	}
+	attributes := a.NodeAttributes(annotate, highlight)
+	if c.scale != nil && highlight != depgraph.Faded {
+		attributes = c.scale.apply(attributes, node)
+	}
+	if _, rule := c.Theme.match(node); rule != nil && highlight != depgraph.Faded {
+		attributes = rule.apply(attributes, highlight)
+	}
+	if overrides := c.Style.nodeOverrides(node); overrides != nil {
+		attributes = overrideAttributes(attributes, overrides)
+	}
+	return attributes
+}
@@ -2,7 +2,9 @@ package query
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
+	"unicode"
 )
 
 type Expr interface {
@@ -34,7 +36,7 @@ func (e *ExprInteger) Value() int     { return e.v }
 func (e *ExprString) Value() string   { return e.v }
 func (e *ExprBool) String() string    { return fmt.Sprintf("%v", e.v) }
 func (e *ExprInteger) String() string { return fmt.Sprintf("%v", e.v) }
-func (e *ExprString) String() string  { return fmt.Sprintf("%v", e.v) }
+func (e *ExprString) String() string  { return quoteString(e.v) }
 func (e *ExprBool) Pos() Position     { return e.p }
 func (e *ExprInteger) Pos() Position  { return e.p }
 func (e *ExprString) Pos() Position   { return e.p }
@@ -102,10 +104,28 @@ type ExprFunc struct {
 
 func (e *ExprFunc) Name() string       { return e.name }
 func (e *ExprFunc) Args() ArgsListExpr { return e.args }
-func (e *ExprFunc) String() string     { return fmt.Sprintf("%s(%v)", e.name, e.args) }
+func (e *ExprFunc) String() string     { return fmt.Sprintf("%s(%v)", quoteString(e.name), e.args) }
 func (e *ExprFunc) Pos() Position      { return e.p }
 func (e *ExprFunc) _expr()             {}
 
+type KeywordArgExpr interface {
+	Expr
+	Key() string
+	Value() Expr
+}
+
+type ExprKeywordArg struct {
+	key   string
+	value Expr
+	p     Position
+}
+
+func (e *ExprKeywordArg) Key() string    { return e.key }
+func (e *ExprKeywordArg) Value() Expr    { return e.value }
+func (e *ExprKeywordArg) String() string { return fmt.Sprintf("%s=%v", quoteString(e.key), e.value) }
+func (e *ExprKeywordArg) Pos() Position  { return e.p }
+func (e *ExprKeywordArg) _expr()         {}
+
 type ArgsListExpr interface {
 	Expr
 	Args() []Expr
@@ -120,13 +140,41 @@ func (e *ExprArgsList) Args() []Expr { return e.values }
 func (e *ExprArgsList) String() string {
 	var strArgs []string
 	for _, arg := range e.values {
-		strArgs = append(strArgs, arg.String())
+		if _, ok := arg.(ArgsListExpr); ok {
+			strArgs = append(strArgs, "("+arg.String()+")")
+		} else {
+			strArgs = append(strArgs, arg.String())
+		}
 	}
-	return fmt.Sprintf("[%s]", strings.Join(strArgs, ", "))
+	return strings.Join(strArgs, ", ")
 }
 func (e *ExprArgsList) Pos() Position { return e.p }
 func (e *ExprArgsList) _expr()        {}
 
+// quoteString returns a representation of the given string value that is tokenized back into the
+// same value. Quotes are added whenever the raw value would otherwise be interpreted differently.
+func quoteString(v string) string {
+	needsQuotes := v == "" || strings.ContainsAny(v, "()=,\"'") || strings.IndexFunc(v, unicode.IsSpace) >= 0
+	switch {
+	case needsQuotes:
+	case v[0] == '-' || v[0] == '+':
+		needsQuotes = true
+	case v == "true" || v == "false" || v == "minus" || v == "union" || v == "inter" || v == "delta":
+		needsQuotes = true
+	default:
+		_, err := strconv.Atoi(v)
+		needsQuotes = err == nil
+	}
+
+	if !needsQuotes {
+		return v
+	}
+	if strings.ContainsRune(v, '"') {
+		return "'" + v + "'"
+	}
+	return `"` + v + `"`
+}
+
 var (
 	_ ValueExpr = &ExprBool{}
 	_ ValueExpr = &ExprInteger{}
@@ -139,5 +187,7 @@ var (
 
 	_ FuncExpr = &ExprFunc{}
 
+	_ KeywordArgExpr = &ExprKeywordArg{}
+
 	_ ArgsListExpr = &ExprArgsList{}
 )
@@ -17,9 +17,12 @@ var (
 	ErrEmptyParenthesis      = errors.New("empty parenthesis")
 	ErrInvalidArgument       = errors.New("invalid argument")
 	ErrInvalidFuncName       = errors.New("invalid function name")
+	ErrInvalidKeyword        = errors.New("invalid keyword")
 	ErrMissingArgument       = errors.New("missing argument")
+	ErrMissingParenthesis    = errors.New("missing closing parenthesis")
 	ErrMissingOperator       = errors.New("missing operator")
 	ErrUnexpectedComma       = errors.New("unexpected comma")
+	ErrUnexpectedEquals      = errors.New("unexpected equals sign")
 	ErrUnexpectedOperator    = errors.New("unexpected operator")
 	ErrUnexpectedParenthesis = errors.New("unexpected parenthesis")
 )
@@ -92,6 +95,16 @@ func (p *parser) parse() (Expr, error) {
 		p.streamIdx++
 	}
 
+	for _, r := range p.ruleStack {
+		if r == groupRule || r == funcRule {
+			end := p.stream[len(p.stream)-1].Pos().end
+			return nil, &parserError{
+				err: ErrMissingParenthesis,
+				pos: pos(end, end),
+			}
+		}
+	}
+
 	for len(p.ruleStack) > 0 {
 		if err := p.reduce(); err != nil {
 			return nil, err
@@ -114,6 +127,7 @@ const (
 	intersectRule             // Expr inter Expr -> BinaryExpr
 	unionRule                 // Expr + Expr -> BinaryExpr
 	subtractRule              // Expr - Expr -> BinaryExpr
+	keywordRule               // String = Expr -> KeywordArgExpr
 	argsListRule              // Expr, Expr -> ArgsListExpr
 	funcRule                  // Expr(ArgListExpr) -> FuncExpr
 	groupRule                 // (Expr) -> Expr
@@ -127,6 +141,7 @@ func (r rule) String() string {
 		intersectRule: "intersect",
 		unionRule:     "union",
 		subtractRule:  "subtract",
+		keywordRule:   "keyword",
 		argsListRule:  "arglist",
 	}[r]
 }
@@ -179,6 +194,24 @@ func (p *parser) shiftPunctuation(next punctuationToken) (bool, error) {
 		p.log.Debug("Appending arglist rule.", zap.String("ruleStack", p.ruleStackString()))
 		return false, nil
 
+	case *tokenEquals:
+		if len(p.exprStack) == 0 {
+			return false, &parserError{
+				err: ErrUnexpectedEquals,
+				pos: next.Pos(),
+			}
+		}
+
+		if len(p.ruleStack) > 0 && p.ruleStack[len(p.ruleStack)-1] < keywordRule {
+			p.log.Debug("Triggering reduce and forcing reprocessing of token.")
+			p.streamIdx--
+			return true, nil
+		}
+
+		p.ruleStack = append(p.ruleStack, keywordRule)
+		p.log.Debug("Appending keyword rule.", zap.String("ruleStack", p.ruleStackString()))
+		return false, nil
+
 	case *tokenParenLeft:
 		p.log.Debug("Computing stack-lengths", zap.Int("exprStackLength", p.exprStackLength()), zap.Int("ruleStackLength", p.ruleStackLength()))
 		if p.exprStackLength() == p.ruleStackLength() {
@@ -264,6 +297,8 @@ func (p *parser) reduce() error {
 		reduceFunc = p.reduceGroupRule
 	case deltaRule, intersectRule, unionRule, subtractRule:
 		reduceFunc = p.reduceOperatorRule(p.ruleStack[len(p.ruleStack)-1])
+	case keywordRule:
+		reduceFunc = p.reduceKeywordRule
 	case argsListRule:
 		reduceFunc = p.reduceArgsListRule
 	}
@@ -286,7 +321,7 @@ func (p *parser) reduceFuncRule() error {
 	default:
 		return &parserError{
 			err: ErrInvalidFuncName,
-			pos: p.stream[p.streamIdx].Pos(),
+			pos: name.Pos(),
 		}
 	}
 
@@ -296,7 +331,7 @@ func (p *parser) reduceFuncRule() error {
 	}
 
 	p.exprStack[len(p.exprStack)-2] = &ExprFunc{
-		name: name.String(),
+		name: name.(*ExprString).Value(),
 		args: args,
 		p:    pos(name.Pos().start, p.stream[p.streamIdx-1].Pos().end),
 	}
@@ -337,7 +372,7 @@ func (p *parser) reduceOperatorRule(r rule) func() error {
 
 		for _, expr := range []Expr{operands.LHS, operands.RHS} {
 			switch expr.(type) {
-			case *ExprBool, *ExprInteger:
+			case *ExprBool, *ExprInteger, *ExprKeywordArg, *ExprArgsList:
 				return &parserError{
 					err: ErrInvalidArgument,
 					pos: expr.Pos(),
@@ -363,6 +398,42 @@ func (p *parser) reduceOperatorRule(r rule) func() error {
 	}
 }
 
+func (p *parser) reduceKeywordRule() error {
+	if len(p.exprStack) < 2 {
+		return &parserError{
+			err: ErrMissingArgument,
+			pos: p.stream[p.streamIdx-1].Pos(),
+		}
+	}
+
+	key, ok := p.exprStack[len(p.exprStack)-2].(*ExprString)
+	if !ok {
+		return &parserError{
+			err: ErrInvalidKeyword,
+			pos: p.exprStack[len(p.exprStack)-2].Pos(),
+		}
+	}
+
+	value := p.exprStack[len(p.exprStack)-1]
+	switch value.(type) {
+	case ArgsListExpr, KeywordArgExpr:
+		return &parserError{
+			err: ErrInvalidArgument,
+			pos: value.Pos(),
+		}
+	}
+
+	p.exprStack[len(p.exprStack)-2] = &ExprKeywordArg{
+		key:   key.Value(),
+		value: value,
+		p:     pos(key.Pos().start, value.Pos().end),
+	}
+	p.exprStack = p.exprStack[:len(p.exprStack)-1]
+	p.ruleStack = p.ruleStack[:len(p.ruleStack)-1]
+	p.log.Debug("Reduced keyword rule.", zap.String("exprStack", p.exprStackString()))
+	return nil
+}
+
 func (p *parser) reduceArgsListRule() error {
 	if len(p.exprStack) < 2 {
 		return &parserError{
@@ -397,7 +468,7 @@ func (p *parser) ruleStackLength() int {
 	var acc int
 	for _, r := range p.ruleStack {
 		switch r {
-		case funcRule, deltaRule, intersectRule, unionRule, subtractRule, argsListRule:
+		case funcRule, deltaRule, intersectRule, unionRule, subtractRule, keywordRule, argsListRule:
 			acc++
 		default:
 			// None
@@ -0,0 +1,48 @@
+//go:build go1.18
+// +build go1.18
+
+package query
+
+import (
+	"testing"
+
+	"github.com/Helcaraxan/gomod/internal/testutil"
+)
+
+func FuzzParse(f *testing.F) {
+	for _, seed := range []string{
+		"foo/bar",
+		"foo - bar delta dead + beef inter null",
+		"foo + bar delta (dead - beef) inter null",
+		"deps(foo, 42, true)",
+		"foo((bar - dead) delta beef + null, 3, true)",
+		"deps(foo) inter (rdeps(bar, 5, true) + dead) - beef",
+		"top(deps(foo) - bar, 10, by=age)",
+		`"quoted string" + 'other:test'`,
+		"(bar))",
+		"foo bar",
+	} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, input string) {
+		log := testutil.TestLogger(t)
+
+		expr, err := Parse(log, input)
+		if err != nil {
+			return
+		}
+
+		printed := expr.String()
+		reparsed, err := Parse(log, printed)
+		if err != nil {
+			t.Fatalf("failed to parse printed expression %q of %q: %v", printed, input, err)
+		}
+		if reparsed.String() != printed {
+			t.Fatalf("printed expression %q of %q does not round-trip: got %q", printed, input, reparsed.String())
+		}
+		if !equalExpr(expr, reparsed) {
+			t.Fatalf("reparsed expression tree of %q differs from that of %q", printed, input)
+		}
+	})
+}
@@ -2,6 +2,7 @@ package query
 
 import (
 	"errors"
+	"reflect"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -90,6 +91,25 @@ func TestParser(t *testing.T) {
 				}},
 			},
 		},
+		"FuncCallKeywordArg": {
+			input: "top(deps(foo) - bar, 10, by=age)",
+			expectedExpr: &ExprFunc{
+				name: "top",
+				args: &ExprArgsList{values: []Expr{
+					&ExprSubtract{BinaryOperands: BinaryOperands{
+						LHS: &ExprFunc{
+							name: "deps",
+							args: &ExprArgsList{values: []Expr{
+								&ExprString{v: "foo"},
+							}},
+						},
+						RHS: &ExprString{v: "bar"},
+					}},
+					&ExprInteger{v: 10},
+					&ExprKeywordArg{key: "by", value: &ExprString{v: "age"}},
+				}},
+			},
+		},
 		"NestedFuncCalls": {
 			input: "foo(bar(test))",
 			expectedExpr: &ExprFunc{
@@ -208,6 +228,18 @@ func TestParserErrors(t *testing.T) {
 			input:       "foo union bar -",
 			expectedErr: ErrMissingArgument,
 		},
+		"InvalidKeyword": {
+			input:       "top(foo, 3=bar)",
+			expectedErr: ErrInvalidKeyword,
+		},
+		"KeywordAsOperand": {
+			input:       "(by=foo) + bar",
+			expectedErr: ErrInvalidArgument,
+		},
+		"UnexpectedEquals": {
+			input:       "=foo",
+			expectedErr: ErrUnexpectedEquals,
+		},
 		"InvalidOperandLHS": {
 			input:       "false inter bar",
 			expectedErr: ErrInvalidArgument,
@@ -216,6 +248,18 @@ func TestParserErrors(t *testing.T) {
 			input:       "foo delta 3",
 			expectedErr: ErrInvalidArgument,
 		},
+		"ArgsListAsOperand": {
+			input:       "(foo, bar) + dead",
+			expectedErr: ErrInvalidArgument,
+		},
+		"UnclosedParenthesis": {
+			input:       "(",
+			expectedErr: ErrMissingParenthesis,
+		},
+		"UnclosedFuncCall": {
+			input:       "0(00000",
+			expectedErr: ErrMissingParenthesis,
+		},
 	}
 
 	for name := range testcases {
@@ -229,3 +273,88 @@ func TestParserErrors(t *testing.T) {
 		})
 	}
 }
+
+func TestParserRoundTrip(t *testing.T) {
+	testcases := map[string]struct {
+		input    string
+		expected string
+	}{
+		"FuncCall": {
+			input:    "deps(foo, 42, true)",
+			expected: "deps(foo, 42, true)",
+		},
+		"NestedArgsList": {
+			input:    "foo((bar, dead), beef)",
+			expected: "foo((bar, dead), beef)",
+		},
+		"KeywordArg": {
+			input:    "top(foo, 3, by = age)",
+			expected: "top(foo, 3, by=age)",
+		},
+		"QuotedKeywords": {
+			input:    `"true" - 'union'`,
+			expected: `("true" - "union")`,
+		},
+		"QuotedSpecialCharacters": {
+			input:    `'foo, bar' + 'say "hi"' + "-1" + '42'`,
+			expected: `((("foo, bar" + 'say "hi"') + "-1") + "42")`,
+		},
+	}
+
+	for name := range testcases {
+		testcase := testcases[name]
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			expr, err := Parse(testutil.TestLogger(t), testcase.input)
+			require.NoError(t, err)
+			assert.Equal(t, testcase.expected, expr.String())
+
+			reparsed, err := Parse(testutil.TestLogger(t), expr.String())
+			require.NoError(t, err)
+			assert.Equal(t, testcase.expected, reparsed.String())
+			assert.True(t, equalExpr(expr, reparsed), "reparsed expression %q differs from %q", reparsed, expr)
+		})
+	}
+}
+
+// equalExpr compares two expression trees while ignoring the positions at which their nodes were
+// parsed.
+func equalExpr(a Expr, b Expr) bool {
+	switch ea := a.(type) {
+	case *ExprBool:
+		eb, ok := b.(*ExprBool)
+		return ok && ea.Value() == eb.Value()
+	case *ExprInteger:
+		eb, ok := b.(*ExprInteger)
+		return ok && ea.Value() == eb.Value()
+	case *ExprString:
+		eb, ok := b.(*ExprString)
+		return ok && ea.Value() == eb.Value()
+	case *ExprFunc:
+		eb, ok := b.(*ExprFunc)
+		return ok && ea.Name() == eb.Name() && equalExpr(ea.Args(), eb.Args())
+	case *ExprKeywordArg:
+		eb, ok := b.(*ExprKeywordArg)
+		return ok && ea.Key() == eb.Key() && equalExpr(ea.Value(), eb.Value())
+	case *ExprArgsList:
+		eb, ok := b.(*ExprArgsList)
+		if !ok || len(ea.Args()) != len(eb.Args()) {
+			return false
+		}
+		for idx := range ea.Args() {
+			if !equalExpr(ea.Args()[idx], eb.Args()[idx]) {
+				return false
+			}
+		}
+		return true
+	case BinaryExpr:
+		eb, ok := b.(BinaryExpr)
+		if !ok || reflect.TypeOf(a) != reflect.TypeOf(b) {
+			return false
+		}
+		return equalExpr(ea.Operands().LHS, eb.Operands().LHS) && equalExpr(ea.Operands().RHS, eb.Operands().RHS)
+	default:
+		return false
+	}
+}
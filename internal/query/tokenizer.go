@@ -78,6 +78,8 @@ func (t *tokenizer) next() (tkn token, err error) {
 		return &tokenUnion{p: pos(p, p+1)}, nil
 	case ',':
 		return &tokenComma{p: pos(p, p+1)}, nil
+	case '=':
+		return &tokenEquals{p: pos(p, p+1)}, nil
 
 	// Quoted string.
 	case '"', '\'':
@@ -101,6 +101,11 @@ func TestSingleTokens(t *testing.T) {
 			expectedToken: &tokenComma{p: pos(0, 1)},
 			expectedErr:   nil,
 		},
+		"Equals": {
+			input:         "=",
+			expectedToken: &tokenEquals{p: pos(0, 1)},
+			expectedErr:   nil,
+		},
 		"True": {
 			input:         "true",
 			expectedToken: &tokenBoolean{p: pos(0, 4), v: true},
@@ -182,6 +187,20 @@ func TestTokenStream(t *testing.T) {
 			},
 			expectedErr: io.EOF,
 		},
+		"KeywordArgument": {
+			input: "top(foo, by=age)",
+			expectedTokens: []token{
+				&tokenString{p: pos(0, 3), v: "top"},
+				&tokenParenLeft{p: pos(3, 4)},
+				&tokenString{p: pos(4, 7), v: "foo"},
+				&tokenComma{p: pos(7, 8)},
+				&tokenString{p: pos(9, 11), v: "by"},
+				&tokenEquals{p: pos(11, 12)},
+				&tokenString{p: pos(12, 15), v: "age"},
+				&tokenParenRight{p: pos(15, 16)},
+			},
+			expectedErr: io.EOF,
+		},
 		"UnclosedString": {
 			input: `rdeps union( foo, "bar)`,
 			expectedTokens: []token{
@@ -66,6 +66,9 @@ type punctuationToken interface {
 type tokenComma struct {
 	p Position
 }
+type tokenEquals struct {
+	p Position
+}
 type tokenParenLeft struct {
 	p Position
 }
@@ -74,15 +77,19 @@ type tokenParenRight struct {
 }
 
 func (t *tokenComma) Pos() Position               { return t.p }
+func (t *tokenEquals) Pos() Position              { return t.p }
 func (t *tokenParenLeft) Pos() Position           { return t.p }
 func (t *tokenParenRight) Pos() Position          { return t.p }
 func (t *tokenComma) String() string              { return ", " }
+func (t *tokenEquals) String() string             { return "=" }
 func (t *tokenParenLeft) String() string          { return "(" }
 func (t *tokenParenRight) String() string         { return ")" }
 func (t *tokenComma) _tokenImpl()                 {}
+func (t *tokenEquals) _tokenImpl()                {}
 func (t *tokenParenLeft) _tokenImpl()             {}
 func (t *tokenParenRight) _tokenImpl()            {}
 func (t *tokenComma) _punctuationTokenImpl()      {}
+func (t *tokenEquals) _punctuationTokenImpl()     {}
 func (t *tokenParenLeft) _punctuationTokenImpl()  {}
 func (t *tokenParenRight) _punctuationTokenImpl() {}
 
@@ -127,6 +134,7 @@ var (
 	_ valueToken = &tokenString{}
 
 	_ punctuationToken = &tokenComma{}
+	_ punctuationToken = &tokenEquals{}
 	_ punctuationToken = &tokenParenLeft{}
 	_ punctuationToken = &tokenParenRight{}
 
@@ -59,6 +59,19 @@ type TestDefinition interface {
 	GoListModOutput() map[string]string
 }
 
+// GraphTestDefinition is a TestDefinition for fixtures that describe a module's dependency graph
+// via the output of the 'go list' and 'go mod graph' commands.
+type GraphTestDefinition struct {
+	ListModOutput map[string]string `yaml:"go_list_mod_output"`
+	ListPkgOutput map[string]string `yaml:"go_list_pkg_output"`
+	GraphOutput   string            `yaml:"go_graph_output"`
+}
+
+func (d *GraphTestDefinition) GoDriverError() bool                { return false }
+func (d *GraphTestDefinition) GoListModOutput() map[string]string { return d.ListModOutput }
+func (d *GraphTestDefinition) GoListPkgOutput() map[string]string { return d.ListPkgOutput }
+func (d *GraphTestDefinition) GoGraphOutput() string              { return d.GraphOutput }
+
 func SetupTestModule(t *testing.T, testDefinitionPath string, testDefinition TestDefinition) string {
 	tempDir := t.TempDir()
 
@@ -0,0 +1,168 @@
+package tree
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/Helcaraxan/gomod/internal/depgraph"
+	"github.com/Helcaraxan/gomod/internal/graph"
+	"github.com/Helcaraxan/gomod/internal/logger"
+)
+
+var ErrUnknownRoot = errors.New("root not found in graph")
+
+// Options determine which part of a dependency graph is printed as a tree.
+type Options struct {
+	// Level of the graph at which to print the tree.
+	Level depgraph.Level
+	// Names of the nodes from which to start the tree. When empty the main module, or its packages,
+	// are used. If these are not part of the graph, all nodes without predecessors are used instead.
+	Roots []string
+	// Maximum depth of the printed tree. Zero means unlimited.
+	MaxDepth int
+}
+
+// Print writes the given dependency graph as an indented tree. Each node is printed with its version
+// and markers for indirect and test-only dependencies. Nodes whose dependencies have already been
+// printed elsewhere in the tree are marked with '(*)' instead of being expanded again.
+func Print(log *logger.Logger, writer io.Writer, g *depgraph.DepGraph, opts *Options) error {
+	roots, err := findRoots(log, g, opts)
+	if err != nil {
+		return err
+	}
+
+	p := &treePrinter{
+		maxDepth: opts.MaxDepth,
+		expanded: map[string]bool{},
+	}
+	for _, root := range roots {
+		marker := p.expand(root)
+		p.lines = append(p.lines, nodeLabel(root, nil)+marker)
+		if marker == "" {
+			p.printChildren(root, "", 1)
+		}
+	}
+
+	if _, err = io.WriteString(writer, strings.Join(p.lines, "\n")+"\n"); err != nil {
+		return fmt.Errorf("failed to print tree: %v", err)
+	}
+	return nil
+}
+
+func findRoots(log *logger.Logger, g *depgraph.DepGraph, opts *Options) ([]graph.Node, error) {
+	nodes := g.Graph.GetLevel(int(opts.Level)).List()
+
+	var roots []graph.Node
+	if len(opts.Roots) > 0 {
+		byName := map[string]graph.Node{}
+		for _, node := range nodes {
+			byName[node.Name()] = node
+		}
+		for _, name := range opts.Roots {
+			node, ok := byName[name]
+			if !ok {
+				log.Error("Could not find the requested root in the dependency graph.", zap.String("root", name))
+				return nil, fmt.Errorf("%q: %w", name, ErrUnknownRoot)
+			}
+			roots = append(roots, node)
+		}
+		return roots, nil
+	}
+
+	if g.Main != nil {
+		for _, node := range nodes {
+			if node.Name() == g.Main.Name() || (opts.Level == depgraph.LevelPackages && node.Parent().Name() == g.Main.Name()) {
+				roots = append(roots, node)
+			}
+		}
+	}
+	if len(roots) > 0 {
+		return roots, nil
+	}
+
+	log.Debug("Main module not part of the graph, using nodes without predecessors as roots.")
+	for _, node := range nodes {
+		if node.Predecessors().Len() == 0 {
+			roots = append(roots, node)
+		}
+	}
+	return roots, nil
+}
+
+type treePrinter struct {
+	maxDepth int
+	expanded map[string]bool
+	lines    []string
+}
+
+// expand records that the dependencies of the node are being printed and returns the marker to add
+// to its label if they already have been.
+func (p *treePrinter) expand(node graph.Node) string {
+	if node.Successors().Len() == 0 {
+		return ""
+	}
+	if p.expanded[node.Hash()] {
+		return " (*)"
+	}
+	p.expanded[node.Hash()] = true
+	return ""
+}
+
+func (p *treePrinter) printChildren(node graph.Node, prefix string, depth int) {
+	if p.maxDepth > 0 && depth > p.maxDepth {
+		return
+	}
+
+	children := node.Successors().List()
+	for idx, child := range children {
+		branch, indent := "├── ", "│   "
+		if idx == len(children)-1 {
+			branch, indent = "└── ", "    "
+		}
+
+		// Only nodes that will have their dependencies printed can be marked as expanded.
+		var marker string
+		if p.maxDepth == 0 || depth < p.maxDepth {
+			marker = p.expand(child)
+		}
+
+		p.lines = append(p.lines, prefix+branch+nodeLabel(child, node)+marker)
+		if marker == "" {
+			p.printChildren(child, prefix+indent, depth+1)
+		}
+	}
+}
+
+func nodeLabel(node graph.Node, parent graph.Node) string {
+	label := node.Name()
+
+	a, ok := node.(depgraph.DataAnnotated)
+	if !ok {
+		return label
+	}
+
+	data := a.NodeData()
+	if version, ok := data["version"].(string); ok && version != "" {
+		label += " " + version
+	}
+
+	var markers []string
+	if parent != nil {
+		if pa, ok := parent.(depgraph.DataAnnotated); ok {
+			if indirect, _ := pa.EdgeData(node)["indirect"].(bool); indirect {
+				markers = append(markers, "indirect")
+			}
+		}
+	}
+	if testOnly, _ := data["test_only"].(bool); testOnly {
+		markers = append(markers, "test")
+	}
+	if len(markers) > 0 {
+		label += " [" + strings.Join(markers, ", ") + "]"
+	}
+	return label
+}
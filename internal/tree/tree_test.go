@@ -0,0 +1,104 @@
+package tree
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Helcaraxan/gomod/internal/depgraph"
+	"github.com/Helcaraxan/gomod/internal/query"
+	"github.com/Helcaraxan/gomod/internal/testutil"
+)
+
+func TestPrint(t *testing.T) {
+	cwd, err := os.Getwd()
+	require.NoError(t, err)
+
+	testcases := map[string]struct {
+		query    string
+		opts     *Options
+		expected string
+	}{
+		"Modules": {
+			opts: &Options{},
+			expected: `test
+├── example.com/a v1.0.0
+│   └── example.com/b v1.1.0
+├── example.com/b v1.1.0
+└── example.com/c v0.1.0 [test]
+    └── example.com/a v1.0.0 (*)
+`,
+		},
+		"MaxDepth": {
+			opts: &Options{MaxDepth: 1},
+			expected: `test
+├── example.com/a v1.0.0
+├── example.com/b v1.1.0
+└── example.com/c v0.1.0 [test]
+`,
+		},
+		"Roots": {
+			opts: &Options{Roots: []string{"example.com/c", "example.com/a"}},
+			expected: `example.com/c v0.1.0 [test]
+└── example.com/a v1.0.0
+    └── example.com/b v1.1.0
+example.com/a v1.0.0 (*)
+`,
+		},
+		"Packages": {
+			opts: &Options{Level: depgraph.LevelPackages},
+			expected: `test
+├── example.com/a v1.0.0
+│   └── example.com/b v1.1.0
+├── example.com/b v1.1.0
+└── example.com/c v0.1.0 [test]
+    └── example.com/a v1.0.0 (*)
+`,
+		},
+		"MainNotSelected": {
+			query: "example.com/**:test",
+			opts:  &Options{},
+			expected: `example.com/c v0.1.0 [test]
+└── example.com/a v1.0.0
+    └── example.com/b v1.1.0
+`,
+		},
+	}
+
+	for name := range testcases {
+		testcase := testcases[name]
+		t.Run(name, func(t *testing.T) {
+			testDir := testutil.SetupTestModule(t, filepath.Join(cwd, "testdata", "Tree.yaml"), &testutil.GraphTestDefinition{})
+
+			log := testutil.TestLogger(t)
+			g, err := depgraph.GetGraph(log, testDir, nil)
+			require.NoError(t, err)
+
+			if testcase.query != "" {
+				q, err := query.Parse(log, testcase.query)
+				require.NoError(t, err)
+				require.NoError(t, g.ApplyQuery(log, q, testcase.opts.Level))
+			}
+
+			output := &strings.Builder{}
+			require.NoError(t, Print(log.Log(), output, g, testcase.opts))
+			assert.Equal(t, testcase.expected, output.String())
+		})
+	}
+
+	t.Run("UnknownRoot", func(t *testing.T) {
+		testDir := testutil.SetupTestModule(t, filepath.Join(cwd, "testdata", "Tree.yaml"), &testutil.GraphTestDefinition{})
+
+		log := testutil.TestLogger(t)
+		g, err := depgraph.GetGraph(log, testDir, nil)
+		require.NoError(t, err)
+
+		err = Print(log.Log(), &strings.Builder{}, g, &Options{Roots: []string{"example.com/unknown"}})
+		assert.True(t, errors.Is(err, ErrUnknownRoot), err)
+	})
+}
@@ -3,9 +3,11 @@ package main
 import (
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"go.uber.org/zap"
@@ -13,11 +15,15 @@ import (
 
 	"github.com/Helcaraxan/gomod/internal/analysis"
 	"github.com/Helcaraxan/gomod/internal/depgraph"
+	"github.com/Helcaraxan/gomod/internal/lock"
 	"github.com/Helcaraxan/gomod/internal/logger"
 	"github.com/Helcaraxan/gomod/internal/parsers"
+	"github.com/Helcaraxan/gomod/internal/policy"
 	"github.com/Helcaraxan/gomod/internal/printer"
 	"github.com/Helcaraxan/gomod/internal/query"
 	"github.com/Helcaraxan/gomod/internal/reveal"
+	"github.com/Helcaraxan/gomod/internal/tree"
+	"github.com/Helcaraxan/gomod/internal/util"
 )
 
 type commonArgs struct {
@@ -62,8 +68,13 @@ func main() {
 
 	rootCmd.AddCommand(
 		initAnalyseCmd(commonArgs),
+		initCheckCmd(commonArgs),
 		initGraphCmd(commonArgs),
+		initLeaksCmd(commonArgs),
+		initLockCmd(commonArgs),
+		initPolicyCmd(commonArgs),
 		initRevealCmd(commonArgs),
+		initTreeCmd(commonArgs),
 		initVersionCmd(commonArgs),
 	)
 
@@ -77,9 +88,16 @@ type graphArgs struct {
 	*commonArgs
 
 	annotate   bool
+	collapse   []string
+	columns    []string
+	edges      *depgraph.EdgeFilter
+	format     printer.Format
+	highlight  string
 	outputPath string
 	packages   bool
+	stdLib     bool
 	style      *printer.StyleOptions
+	theme      *printer.Theme
 
 	query string
 }
@@ -89,19 +107,54 @@ func initGraphCmd(cArgs *commonArgs) *cobra.Command {
 		commonArgs: cArgs,
 	}
 
-	var style string
+	var edges, format, style, styleFile, theme string
 	graphCmd := &cobra.Command{
 		Use:   "graph <query>",
 		Short: graphShort,
 		Long:  graphLong,
 		Args:  cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if cmd.Flags().Changed("style") {
-				styleOptions, err := parsers.ParseStyleConfiguration(cmdArgs.log.Domain(logger.InitDomain), style)
+			if styleFile != "" {
+				styleOptions, f, err := parsers.ParseStyleFile(cmdArgs.log.Domain(logger.InitDomain), styleFile)
 				if err != nil {
 					return err
 				}
 				cmdArgs.style = styleOptions
+				if f != nil && !cmd.Flags().Changed("format") {
+					format = f.String()
+				}
+			}
+			if cmd.Flags().Changed("style") {
+				// Inline options take precedence over those of the style file.
+				if cmdArgs.style == nil {
+					cmdArgs.style = &printer.StyleOptions{}
+				}
+				if err := parsers.ApplyStyleConfiguration(cmdArgs.log.Domain(logger.InitDomain), cmdArgs.style, style); err != nil {
+					return err
+				}
+			}
+			if theme != "" {
+				t, err := parsers.ParseTheme(cmdArgs.log.Domain(logger.InitDomain), theme)
+				if err != nil {
+					return err
+				}
+				cmdArgs.theme = t
+			}
+			if edges != "" {
+				filter, err := parsers.ParseEdgeFilter(cmdArgs.log.Domain(logger.InitDomain), edges)
+				if err != nil {
+					return err
+				}
+				cmdArgs.edges = filter
+			}
+			f, err := parsers.ParseFormat(cmdArgs.log.Domain(logger.InitDomain), format)
+			if err != nil {
+				return err
+			}
+			cmdArgs.format = f
+			if len(cmdArgs.columns) > 0 && f != printer.FormatCSV && f != printer.FormatTSV {
+				cmdArgs.log.Domain(logger.InitDomain).Error("The '--columns' flag is only supported by the 'csv' and 'tsv' output formats.", zap.Stringer("format", f))
+				return errors.New("invalid flag combination")
 			}
 			if len(args) == 0 {
 				cmdArgs.query = "**:test"
@@ -113,15 +166,26 @@ func initGraphCmd(cArgs *commonArgs) *cobra.Command {
 	}
 
 	graphCmd.Flags().BoolVarP(&cmdArgs.annotate, "annotate", "a", false, "Annotate the graph's nodes and edges with version information")
+	graphCmd.Flags().StringVar(&edges, "edges", "", "Only print edges between modules whose weight, the number of package imports they represent, matches a filter such as 'weight>5'.")
+	graphCmd.Flags().StringVarP(&format, "format", "f", "dot", "Output format of the graph: 'dot', 'mermaid', 'graphml', 'gexf', 'json', 'html', 'csv' or 'tsv'.")
+	graphCmd.Flags().StringSliceVar(&cmdArgs.collapse, "collapse", nil, "Comma-separated list of glob patterns. All nodes matching a pattern are merged into a single node before printing.")
+	graphCmd.Flags().StringSliceVar(&cmdArgs.columns, "columns", nil, "Comma-separated list of the columns to include in 'csv' or 'tsv' output. Known columns: "+strings.Join(printer.TableColumns(), ", ")+".")
+	graphCmd.Flags().StringVar(&cmdArgs.highlight, "highlight", "", "Query selecting nodes to emphasise within the graph selected by the main query. All other nodes and edges are faded.")
 	graphCmd.Flags().StringVarP(&cmdArgs.outputPath, "output", "o", "", "If set dump the output to this location")
 	graphCmd.Flags().BoolVarP(&cmdArgs.packages, "packages", "p", false, "Operate at package-level instead of module-level on the dependency graph.")
+	graphCmd.Flags().BoolVar(&cmdArgs.stdLib, "std", false, "Include standard library packages as part of a synthetic 'std' module. Only queries that explicitly target them via 'std' or 'std:<pattern>' will select them.")
 	graphCmd.Flags().StringVar(&style, "style", "", "Set style options that add decorations and optimisations to the produced 'dot' output.")
+	graphCmd.Flags().StringVar(&styleFile, "style-file", "", "Read style options from a YAML file. Options set via '--style' take precedence.")
+	graphCmd.Flags().StringVar(&theme, "theme", "", "Path to a YAML file with rules that assign colours to the nodes of the graph.")
 
 	return graphCmd
 }
 
 func runGraphCmd(args *graphArgs) error {
-	graph, err := depgraph.GetGraph(args.log, "")
+	graph, err := depgraph.GetGraph(args.log, "", &depgraph.GraphOptions{
+		StdLib:  args.stdLib,
+		Updates: args.style != nil && args.style.ColourBy == printer.ColourByBacklog,
+	})
 	if err != nil {
 		return err
 	}
@@ -137,8 +201,64 @@ func runGraphCmd(args *graphArgs) error {
 	if err = graph.ApplyQuery(args.log, q, l); err != nil {
 		return err
 	}
+	if args.edges != nil {
+		if err = graph.FilterEdges(args.log, *args.edges, l); err != nil {
+			return err
+		}
+	}
+
+	// The highlight query is evaluated before collapsing nodes so that it can target their members.
+	var highlighted map[string]bool
+	if args.highlight != "" {
+		if highlighted, err = computeHighlight(graph, args, l); err != nil {
+			return err
+		}
+	}
+	if len(args.collapse) > 0 {
+		if graph, err = graph.Collapse(args.log, args.collapse, l); err != nil {
+			return err
+		}
+	}
+
+	var highlight map[string]bool
+	if highlighted != nil {
+		highlight = map[string]bool{}
+		for _, node := range graph.Graph.GetLevel(int(l)).List() {
+			members := []string{node.Name()}
+			if c, ok := node.(*depgraph.Collapsed); ok {
+				members = c.Members()
+			}
+			for _, member := range members {
+				if highlighted[member] {
+					highlight[node.Hash()] = true
+				}
+			}
+		}
+	}
 	args.log.Log().Debug("Printing graph.")
-	return printResult(graph, args)
+	return printResult(graph, highlight, args)
+}
+
+// computeHighlight returns the names of the nodes in the graph that are selected by the highlight
+// query.
+func computeHighlight(graph *depgraph.DepGraph, args *graphArgs, l depgraph.Level) (map[string]bool, error) {
+	q, err := query.Parse(args.log, args.highlight)
+	if err != nil {
+		return nil, err
+	}
+	selection, err := graph.Query(args.log, q, l)
+	if err != nil {
+		return nil, err
+	}
+
+	highlighted := map[string]bool{}
+	for _, node := range selection.Graph.GetLevel(int(l)).List() {
+		highlighted[node.Name()] = true
+	}
+	if len(highlighted) == 0 {
+		args.log.Log().Warn("The highlight query did not match any nodes in the graph.", zap.String("query", args.highlight))
+	}
+	return highlighted, nil
 }
 
 type analyseArgs struct {
@@ -162,7 +282,7 @@ func initAnalyseCmd(cArgs *commonArgs) *cobra.Command {
 }
 
 func runAnalyseCmd(args *analyseArgs) error {
-	graph, err := depgraph.GetGraph(args.log, "")
+	graph, err := depgraph.GetGraph(args.log, "", nil)
 	if err != nil {
 		return err
 	}
@@ -173,6 +293,243 @@ func runAnalyseCmd(args *analyseArgs) error {
 	return analysisResult.Print(os.Stdout)
 }
 
+type checkArgs struct {
+	*commonArgs
+
+	stdLib bool
+
+	rulesPath string
+}
+
+func initCheckCmd(cArgs *commonArgs) *cobra.Command {
+	cmdArgs := &checkArgs{
+		commonArgs: cArgs,
+	}
+
+	checkCmd := &cobra.Command{
+		Use:   "check <rules-file>",
+		Short: checkShort,
+		Long:  checkLong,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmdArgs.rulesPath = args[0]
+			return runCheckCmd(cmd, cmdArgs)
+		},
+	}
+
+	checkCmd.Flags().BoolVar(&cmdArgs.stdLib, "std", false, "Include standard library packages as part of a synthetic 'std' module.")
+
+	return checkCmd
+}
+
+func runCheckCmd(cmd *cobra.Command, args *checkArgs) error {
+	rules, err := parsers.ParseRules(args.log, args.rulesPath)
+	if err != nil {
+		return err
+	}
+
+	graph, err := depgraph.GetGraph(args.log, "", &depgraph.GraphOptions{StdLib: args.stdLib})
+	if err != nil {
+		return err
+	}
+
+	var failed int
+	for _, rule := range rules {
+		result, err := graph.CheckRule(args.log, rule)
+		if err != nil {
+			return err
+		}
+		if len(result.Violations) > 0 {
+			failed++
+		}
+		if err = result.Print(os.Stdout); err != nil {
+			return err
+		}
+	}
+	if failed > 0 {
+		// Violations have been reported above so the usage would only hide them.
+		cmd.SilenceUsage = true
+		return fmt.Errorf("%d of %d rules failed", failed, len(rules))
+	}
+	return nil
+}
+
+type leaksArgs struct {
+	*commonArgs
+
+	testPatterns []string
+}
+
+func initLeaksCmd(cArgs *commonArgs) *cobra.Command {
+	cmdArgs := &leaksArgs{
+		commonArgs: cArgs,
+	}
+
+	leaksCmd := &cobra.Command{
+		Use:   "leaks",
+		Short: leaksShort,
+		Long:  leaksLong,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runLeaksCmd(cmd, cmdArgs)
+		},
+	}
+
+	leaksCmd.Flags().StringSliceVar(&cmdArgs.testPatterns, "test-patterns", depgraph.DefaultTestPatterns, "Comma-separated list of glob patterns matching the paths of test-oriented modules or packages.")
+
+	return leaksCmd
+}
+
+func runLeaksCmd(cmd *cobra.Command, args *leaksArgs) error {
+	graph, err := depgraph.GetGraph(args.log, "", nil)
+	if err != nil {
+		return err
+	}
+
+	leaks, err := graph.FindTestLeaks(args.log, args.testPatterns)
+	if err != nil {
+		return err
+	}
+	if err = leaks.Print(os.Stdout); err != nil {
+		return err
+	}
+	if len(leaks) > 0 {
+		// Leaks have been reported above so the usage would only hide them.
+		cmd.SilenceUsage = true
+		return fmt.Errorf("%d test-oriented packages are imported by non-test code", len(leaks))
+	}
+	return nil
+}
+
+type lockArgs struct {
+	*commonArgs
+
+	path   string
+	verify bool
+}
+
+func initLockCmd(cArgs *commonArgs) *cobra.Command {
+	cmdArgs := &lockArgs{
+		commonArgs: cArgs,
+	}
+
+	lockCmd := &cobra.Command{
+		Use:   "lock",
+		Short: lockShort,
+		Long:  lockLong,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runLockCmd(cmd, cmdArgs)
+		},
+	}
+
+	lockCmd.Flags().StringVar(&cmdArgs.path, "file", lock.DefaultPath, "Location of the lock file.")
+	lockCmd.Flags().BoolVar(&cmdArgs.verify, "verify", false, "Compare the dependency graph with the lock file instead of updating it.")
+
+	return lockCmd
+}
+
+func runLockCmd(cmd *cobra.Command, args *lockArgs) error {
+	graph, err := depgraph.GetGraph(args.log, "", nil)
+	if err != nil {
+		return err
+	}
+	current := lock.New(graph)
+
+	if !args.verify {
+		return current.Write(args.log.Log(), args.path)
+	}
+
+	locked, err := lock.Read(args.log.Log(), args.path)
+	if err != nil {
+		return err
+	}
+	diff := lock.Compare(locked, current)
+	if err = diff.Print(os.Stdout); err != nil {
+		return err
+	}
+	if diff.HasAdditions() {
+		// The differences have been reported above so the usage would only hide them.
+		cmd.SilenceUsage = true
+		return fmt.Errorf("the dependency graph contains modules or edges that are not part of %q, run 'gomod lock' to approve them", args.path)
+	} else if diff.HasRemovals() {
+		args.log.Log().Info("The lock file lists modules or edges that are no longer part of the dependency graph. Run 'gomod lock' to remove them.", zap.String("path", args.path))
+	}
+	return nil
+}
+
+type policyArgs struct {
+	*commonArgs
+
+	format     policy.Format
+	outputPath string
+
+	policyPath string
+}
+
+func initPolicyCmd(cArgs *commonArgs) *cobra.Command {
+	cmdArgs := &policyArgs{
+		commonArgs: cArgs,
+	}
+
+	var format string
+	policyCmd := &cobra.Command{
+		Use:   "policy <policy-file>",
+		Short: policyShort,
+		Long:  policyLong,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			f, err := parsers.ParsePolicyFormat(cmdArgs.log.Domain(logger.InitDomain), format)
+			if err != nil {
+				return err
+			}
+			cmdArgs.format = f
+			cmdArgs.policyPath = args[0]
+			return runPolicyCmd(cmd, cmdArgs)
+		},
+	}
+
+	policyCmd.Flags().StringVarP(&format, "format", "f", "text", "Output format of the findings: 'text', 'json' or 'sarif'.")
+	policyCmd.Flags().StringVarP(&cmdArgs.outputPath, "output", "o", "", "If set dump the output to this location")
+
+	return policyCmd
+}
+
+func runPolicyCmd(cmd *cobra.Command, args *policyArgs) error {
+	p, err := parsers.ParsePolicy(args.log, args.policyPath)
+	if err != nil {
+		return err
+	}
+
+	graph, err := depgraph.GetGraph(args.log, "", nil)
+	if err != nil {
+		return err
+	}
+	findings := policy.Evaluate(args.log.Log(), graph, p)
+
+	w := io.Writer(os.Stdout)
+	if args.outputPath != "" {
+		f, err := util.PrepareOutputPath(args.log.Log(), args.outputPath)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			_ = f.Close()
+		}()
+		w = f
+	}
+	if err = policy.Print(w, findings, args.format); err != nil {
+		return err
+	}
+
+	if len(findings) > 0 {
+		// Findings have been reported above so the usage would only hide them.
+		cmd.SilenceUsage = true
+		return fmt.Errorf("%d modules break the policy", len(findings))
+	}
+	return nil
+}
+
 type revealArgs struct {
 	*commonArgs
 	sources []string
@@ -199,7 +556,7 @@ func initRevealCmd(cArgs *commonArgs) *cobra.Command {
 }
 
 func runRevealCmd(args *revealArgs) error {
-	graph, err := depgraph.GetGraph(args.log, "")
+	graph, err := depgraph.GetGraph(args.log, "", nil)
 	if err != nil {
 		return err
 	}
@@ -210,6 +567,73 @@ func runRevealCmd(args *revealArgs) error {
 	return replacements.Print(args.log.Log(), os.Stdout, args.sources, args.targets)
 }
 
+type treeArgs struct {
+	*commonArgs
+
+	depth    int
+	packages bool
+	roots    []string
+	stdLib   bool
+
+	query string
+}
+
+func initTreeCmd(cArgs *commonArgs) *cobra.Command {
+	cmdArgs := &treeArgs{
+		commonArgs: cArgs,
+	}
+
+	treeCmd := &cobra.Command{
+		Use:   "tree [query]",
+		Short: treeShort,
+		Long:  treeLong,
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				cmdArgs.query = "**:test"
+			} else {
+				cmdArgs.query = args[0]
+			}
+			return runTreeCmd(cmdArgs)
+		},
+	}
+
+	treeCmd.Flags().IntVarP(&cmdArgs.depth, "depth", "d", 0, "Maximum depth of the printed tree. Zero means unlimited.")
+	treeCmd.Flags().BoolVarP(&cmdArgs.packages, "packages", "p", false, "Operate at package-level instead of module-level on the dependency graph.")
+	treeCmd.Flags().StringSliceVarP(&cmdArgs.roots, "roots", "r", nil, "Nodes from which to start the tree instead of the main module.")
+	treeCmd.Flags().BoolVar(&cmdArgs.stdLib, "std", false, "Include standard library packages as part of a synthetic 'std' module.")
+
+	return treeCmd
+}
+
+func runTreeCmd(args *treeArgs) error {
+	if args.depth < 0 {
+		return errors.New("the tree depth can not be negative")
+	}
+
+	graph, err := depgraph.GetGraph(args.log, "", &depgraph.GraphOptions{StdLib: args.stdLib})
+	if err != nil {
+		return err
+	}
+
+	q, err := query.Parse(args.log, args.query)
+	if err != nil {
+		return err
+	}
+	l := depgraph.LevelModules
+	if args.packages {
+		l = depgraph.LevelPackages
+	}
+	if err = graph.ApplyQuery(args.log, q, l); err != nil {
+		return err
+	}
+	return tree.Print(args.log.Log(), os.Stdout, graph, &tree.Options{
+		Level:    l,
+		Roots:    args.roots,
+		MaxDepth: args.depth,
+	})
+}
+
 type versionArgs struct {
 	*commonArgs
 }
@@ -272,7 +696,7 @@ func checkGoModulePresence(log *logger.Logger) error {
 	return errors.New("missing go module")
 }
 
-func printResult(g *depgraph.DepGraph, args *graphArgs) error {
+func printResult(g *depgraph.DepGraph, highlight map[string]bool, args *graphArgs) error {
 	l := printer.LevelModules
 	if args.packages {
 		l = printer.LevelPackages
@@ -282,6 +706,10 @@ func printResult(g *depgraph.DepGraph, args *graphArgs) error {
 		Granularity: l,
 		OutputPath:  args.outputPath,
 		Style:       args.style,
+		Theme:       args.theme,
 		Annotate:    args.annotate,
+		Highlight:   highlight,
+		Format:      args.format,
+		Columns:     args.columns,
 	})
 }
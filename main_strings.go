@@ -36,15 +36,37 @@ should be printed. The query language itself supports the following syntax:
 
 - Exact or prefix path queries: foo.com/bar or foo.com/bar/...
 - Inclusion of test-only dependencies: test(foo.com/bar)
+- Standard library packages when using the '--std' flag: std or std:net/...
 - Dependency queries: 'deps(foo.com/bar)' or 'rdeps(foo.com/bar)
 - Depth-limited variants of the above: 'deps(foo.com/bar, 5)'
 - Recursive removal of single-parent leaf-nodes: shared(foo.com/bar)'
+- Nodes without dependencies or reverse dependencies within a set: 'leaves(foo.com/**)' or
+  'roots(foo.com/**)'
+- Nodes at an exact distance from a set: 'depth(foo.com/bar, 2)'
+- Selection of the highest ranking nodes for a metric: 'top(foo.com/**, 10, by=rdeps)'
+  where the metric is one of 'in_degree' (default), 'out_degree', 'rdeps', 'deps',
+  'packages' or 'age'.
 - Various set operations: X + Y, X - Y, X inter Y, X delta Y.
 
 An example query:
 
 gomod graph -p 'deps(foo.com/bar/...) inter deps(test(test.io/pkg/tool))'
 
+A second query can be passed via '--highlight' to emphasise part of the selected
+graph without removing the surrounding context. Highlighted nodes and the edges
+between them are drawn in bold and red while all others are faded. Formats that
+carry node and edge properties mark them with a 'highlighted' attribute.
+
+Nodes whose names match one of the glob patterns passed via '--collapse' are
+merged into a single node per pattern after the query has been evaluated. The
+edges of the merged nodes are combined and labelled with the number of edges they
+represent. A collapsed node is highlighted if any of its members is.
+
+At module level the weight of an edge is the number of package imports that it
+represents. Heavier edges are drawn with a thicker line and, when using the
+'edge_weights' style option, are labelled with their weight. The '--edges' flag only keeps the edges whose weight
+matches a filter such as 'weight>5', 'weight>=2' or 'weight=0'.
+
 The generated graph is colour and format coded:
 - Each module, or group of packages belonging to the same module, has a distinct
   colour.
@@ -53,6 +75,30 @@ The generated graph is colour and format coded:
 - Test-only edges are recognisable by a light blue colour.
 - Edges reflecting indirect module dependencies are marked with dashed instead
   of continuous lines.
+- Edges between modules are drawn thicker the more package imports they
+  represent.
+
+The graph is printed in the DOT format by default. Use '--format mermaid' to
+produce a Mermaid flowchart instead which uses the same colour and format
+coding.
+
+For use with graph analysis tools such as yEd or Gephi the graph can also be
+exported with '--format graphml' or '--format gexf'. Nodes and edges then carry
+their version, replacement, test-only and indirect information as attributes.
+The same data is available as a JSON document with '--format json'. Finally
+'--format html' produces a standalone HTML page with an interactive viewer that
+supports panning, zooming, searching and highlighting of (reverse) dependencies.
+
+With '--format csv' or '--format tsv' the graph is printed as a table of nodes
+followed by a table of edges. When an output path such as 'deps.csv' is set they
+are written to 'deps.nodes.csv' and 'deps.edges.csv' instead. The printed
+columns can be selected with '--columns'.
+
+The colours of nodes can be customised with a YAML theme file passed via
+'--theme'. Its rules assign 'fill', 'text' and 'border' colours to the nodes
+matching a glob pattern ('match'), being test-only ('test_only'), being replaced
+('replaced') or whose version is of a certain age ('age', such as '>2y'). The
+first matching rule applies and other nodes keep their default colours.
 
 Other visual aspects (when run through the 'dot' tool) can be tuned with the
 '--style' flag. You can specify any formatting options as
@@ -61,31 +107,181 @@ Other visual aspects (when run through the 'dot' tool) can be tuned with the
 
 out of the following list:
 
-- 'scale_nodes': one of 'true' or 'false' (default 'false'). This will scale the
-                 size of each node of the graph based on the number of inbound
-                 and outbound dependencies it has.
+- 'scale_nodes': one of 'true', 'false', 'edges', 'files', 'loc' or 'packages'
+                 (default 'false'). This will scale the size of each node of the
+                 graph based on respectively the number of inbound and outbound
+                 dependencies it has ('true' and 'edges'), the number of Go
+                 files or lines of code of its packages, or the number of
+                 packages of its module.
+
+- 'legend':      one of 'true' or 'false' (default 'false'). This adds a legend
+                 explaining the colours and line styles used in the graph.
+
+- 'color_by':    one of 'name', 'age', 'backlog', 'rdeps' or 'packages' (default
+                 'name'). Other values than 'name' replace the colours derived
+                 from the module's name with a gradient from green to red based
+                 on respectively the age of the module's version, the time since
+                 which a newer version is available, the number of direct
+                 dependents or the module's number of packages. Using 'backlog'
+                 requires network access to retrieve the available updates.
+
+- 'rankdir':     one of 'TB', 'BT', 'LR' or 'RL' (default 'TB'). The direction in
+                 which the graph is laid out.
+
+- 'ranksep':     the minimum distance between ranks in inches, such as '0.8'.
 
-- 'cluster':     one of 'off', 'shared', 'full' (default 'off'). This option
-                 will generate clusters in the image that force the grouping of
-                 shared dependencies together. The result is a tighter graph of
+- 'splines':     one of 'ortho', 'polyline', 'spline', 'line', 'curved' or 'none'.
+                 How edges are drawn. Defaults to 'ortho' unless annotating.
+
+- 'edge_weights': one of 'true' or 'false' (default 'false'). This labels each
+                 edge between modules with the number of package imports that
+                 it represents.
+
+- 'font':        the name of the font used for all labels.
+
+- 'same_rank':   a glob pattern. The matching nodes are placed on the same rank.
+                 Can be repeated to form several groups. Commas inside the
+                 pattern's '{...}' alternatives do not separate options.
+
+- 'pin_main':    one of 'true' or 'false' (default 'false'). This places the main
+                 module, or its packages, alone on the top rank.
+
+- 'max_rank':    the maximum number of edges between a printed node and a node
+                 without dependents, such as the main module. Deeper nodes are
+                 not printed. Zero means unlimited (default '0').
+
+- 'cluster':     one of 'off', 'shared', 'full', 'module', 'module+shared' or
+                 'module+full' (default 'off'). This option will generate
+                 clusters in the image that force the grouping of shared
+                 dependencies together. The result is a tighter graph of
                  reduced size with less "holes" but which might have less
                  visible or understandable edges. When set to 'shared' only
                  dependencies with a single inbound edge are considered and
                  clustered according to the commonality of that ancestor. When
                  set to 'full' any two dependencies that have an identical set
                  of inbound edges are clustered together.
+                 When printing packages, 'module' wraps the packages of each
+                 module in a box labelled with the module's name and coloured
+                 like the module. Combined with 'shared' or 'full' the above
+                 clustering is additionally applied within each module.
                 
                  WARNING: Using the 'cluster' option can dramatically increase
                           the time required to generate image files, especially
                           for larger dependency graphs. But it's for the latter
                           that it can also greatly improve the readability of
                           the final image.
+
+The same options can be set in a YAML file passed via '--style-file', with one
+key per option. Such a file can additionally set the output 'format' and list
+'nodes' whose DOT attributes are overridden:
+
+  nodes:
+    - match: github.com/my-org/**
+      attributes:
+        shape: ellipse
+
+Options set via '--style' take precedence over those of the style file, as does
+the '--format' flag. Any 'same_rank' patterns set via '--style' replace those
+listed in the style file.
 `
 
 	analyseShort = `Analyse the graph of dependencies for this Go module and output interesting
 statistics.`
 
+	checkShort = "Check that the dependency graph of a Go module satisfies a set of rules."
+	checkLong  = `Check that the dependency graph satisfies the rules listed in a YAML file, such as
+layering constraints between packages or dependencies that are forbidden in production
+code. Each rule has a 'name' and a 'query' using the same syntax as 'gomod graph':
+
+  rules:
+    - name: api does not use storage
+      level: packages
+      from: ourorg.com/svc/api
+      query: deps(ourorg.com/svc/api) inter (ourorg.com/svc/storage + ourorg.com/svc/storage/**)
+    - name: no testify in production
+      level: packages
+      query: github.com/stretchr/testify/**
+    - name: only approved logging libraries
+      query: deps(ourorg.com/svc) inter (go.uber.org/** + github.com/sirupsen/**)
+      subset_of: go.uber.org/zap + go.uber.org/multierr + go.uber.org/atomic
+
+A rule fails when its query selects any nodes or, when 'subset_of' is set, any nodes that
+are not also selected by that second query. Queries are evaluated on modules unless the
+rule's 'level' is 'packages'. Each import of a violating node is printed with the shortest
+chain of dependencies through which it is reached, starting at the main module or at the
+nodes selected by the rule's 'from' query. Chains through non-test dependencies are
+preferred.
+
+The command exits with a non-zero status if any of the rules fails.
+`
+
+	leaksShort = "Find test-oriented modules and packages that are imported by non-test code."
+	leaksLong  = `Find the testing libraries, mocks and test helpers that are imported, directly or
+indirectly, by the non-test code of the main module and thus end up in its binaries. Each
+leaked package is printed with the shortest chain of non-test imports through which it
+is reached.
+
+Test-oriented code is recognised by glob patterns matched against the paths of packages
+and of their modules. The patterns can be set via '--test-patterns' and default to:
+
+  github.com/stretchr/testify, github.com/golang/mock, go.uber.org/mock,
+  github.com/onsi/ginkgo, github.com/onsi/gomega, gotest.tools, **/testutil,
+  **/testutils, **/testhelpers, **/mocks
+
+Packages of the main module that match a pattern are not considered as non-test code
+themselves. The command exits with a non-zero status if any leak is found.
+`
+
+	lockShort = "Record the modules of the dependency graph and their dependencies in a lock file."
+	lockLong  = `Write a snapshot of the modules of the dependency graph, including test-only ones, and
+of the dependencies between them to a lock file that can be committed alongside 'go.mod'.
+Versions are not recorded, so upgrading an already approved module does not change it.
+
+With '--verify' the current dependency graph is compared with the lock file instead and
+the added and removed modules and dependencies are printed as a diff. The command exits
+with a non-zero status if the graph contains any module or dependency that is not part of
+the lock file. Modules and dependencies that are no longer used do not fail the check.
+`
+
+	policyShort = "Check the modules of the dependency graph against lists of banned and allowed modules."
+	policyLong  = `Check that the dependency graph of a Go module only contains approved modules. The
+policy is read from a YAML file listing banned and allowed modules. Each entry has a glob
+pattern matched against module paths, an optional range of versions and an optional reason:
+
+  banned:
+    - module: github.com/pkg/errors
+      reason: use the standard library's errors package
+    - module: github.com/gogo/protobuf
+      versions: <v1.3.2
+      reason: CVE-2021-3121
+  allowed:
+    - module: go.uber.org/**
+    - module: github.com/spf13/cobra
+      versions: '>=v1.0.0, <v2.0.0'
+
+Version ranges are comma-separated lists of complete semantic versions such as 'v1.2.3',
+each optionally preceded by one of '>', '>=', '<', '<=' or '='. A module is reported when it matches a banned entry or, if
+the policy lists any allowed modules, when it matches none of them. Each finding is
+printed with the shortest chain of modules through which the main module depends on it.
+
+The findings can be printed as 'text', 'json' or 'sarif', the latter being understood by
+code-scanning tools and CI annotators. The command exits with a non-zero status if any
+module breaks the policy.
+`
+
 	revealShort = "Reveal 'hidden' replace'd modules in your direct and direct independencies."
 
+	treeShort = "Print the dependency graph of a Go module as a tree in the terminal."
+	treeLong  = `Print the part of your dependency graph selected by a query as an indented tree,
+similar to 'npm ls'. The query uses the same syntax as 'gomod graph' and defaults to the
+full graph including test-only dependencies.
+
+The tree starts at the main module, or at its packages when using '--packages'. Other
+starting points can be specified with '--roots'. Each node is shown with its version and
+is marked with '[indirect]' or '[test]' for indirect and test-only dependencies. Nodes
+whose dependencies were already printed elsewhere in the tree are marked with '(*)' and
+not expanded again.
+`
+
 	versionShort = "Display the version of the gomod tool."
 )
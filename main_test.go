@@ -15,6 +15,11 @@ import (
 
 var regenerate = flag.Bool("regenerate", false, "Instead of testing the output, use the generated output to refresh the golden images.")
 
+var formatExtensions = map[printer.Format]string{
+	printer.FormatDOT:     ".dot",
+	printer.FormatMermaid: ".mmd",
+}
+
 func TestGraphGeneration(t *testing.T) {
 	testcases := map[string]struct {
 		expectedFileBase string
@@ -25,7 +30,7 @@ func TestGraphGeneration(t *testing.T) {
 			dotArgs: &graphArgs{
 				query: "deps(github.com/Helcaraxan/gomod:test)",
 				style: &printer.StyleOptions{
-					ScaleNodes: true,
+					ScaleNodes: printer.ScaleByEdges,
 					Cluster:    printer.Full,
 				},
 			},
@@ -45,6 +50,15 @@ func TestGraphGeneration(t *testing.T) {
 				style:    &printer.StyleOptions{},
 			},
 		},
+		"TargetDependencyMermaid": {
+			expectedFileBase: "dependency-chains",
+			dotArgs: &graphArgs{
+				annotate: true,
+				format:   printer.FormatMermaid,
+				query:    "rdeps(github.com/stretchr/testify)",
+				style:    &printer.StyleOptions{},
+			},
+		},
 	}
 
 	for name := range testcases {
@@ -54,19 +68,20 @@ func TestGraphGeneration(t *testing.T) {
 
 			cArgs := &commonArgs{log: testutil.TestLogger(t)}
 
-			// Test the dot generation.
+			// Test the graph generation.
 			dotArgs := *testcase.dotArgs
 			dotArgs.commonArgs = cArgs
-			dotArgs.outputPath = filepath.Join(tempDir, testcase.expectedFileBase+".dot")
+			fileName := testcase.expectedFileBase + formatExtensions[dotArgs.format]
+			dotArgs.outputPath = filepath.Join(tempDir, fileName)
 
 			require.NoError(t, runGraphCmd(&dotArgs))
-			actual, err := ioutil.ReadFile(filepath.Join(tempDir, testcase.expectedFileBase+".dot"))
+			actual, err := ioutil.ReadFile(filepath.Join(tempDir, fileName))
 			require.NoError(t, err)
 			if *regenerate {
-				require.NoError(t, ioutil.WriteFile(filepath.Join("images", testcase.expectedFileBase+".dot"), actual, 0644))
+				require.NoError(t, ioutil.WriteFile(filepath.Join("images", fileName), actual, 0644))
 			} else {
 				var expected []byte
-				expected, err = ioutil.ReadFile(filepath.Join("images", testcase.expectedFileBase+".dot"))
+				expected, err = ioutil.ReadFile(filepath.Join("images", fileName))
 				require.NoError(t, err)
 				assert.Equal(t, string(expected), string(actual))
 			}